@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thxrsxm/res/internal/backbone"
+)
+
+// runBackbone prints the literals true in every model of the clause set and the variables that are not forced,
+// i.e. true in some models and false in others.
+// An unsatisfiable clause set has no backbone, so only its verdict is printed.
+func runBackbone(args []string) error {
+	fs := flag.NewFlagSet("backbone", flag.ExitOnError)
+	queries := fs.Bool("queries", false, "print the number of solver queries")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res backbone [options] [--] <clause1> <clause2> ...\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	set, err := parseClauses(fs.Args())
+	if err != nil {
+		return err
+	}
+	result, err := backbone.Compute(context.Background(), set, nil)
+	if err != nil {
+		return err
+	}
+	if result.Unsatisfiable {
+		printResult(true)
+		return nil
+	}
+	fmt.Printf("forced:     %s\n", formatModel(result.Forced))
+	fmt.Printf("not forced: %s\n", formatModel(result.Unforced))
+	if *queries {
+		fmt.Printf("queries: %d\n", result.Queries)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/thxrsxm/res/internal/bench"
+	"github.com/thxrsxm/res/internal/solver"
+)
+
+// runBench runs a directory of instances through the engines and prints a report.
+// If a baseline is given, the run fails when a measurement regressed.
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	engines := fs.String("engines", "", "comma-separated `names` of the engines to run (default all)")
+	format := fs.String("format", "csv", "report `format`: csv or json")
+	baseline := fs.String("baseline", "", "compare against a JSON report saved from an earlier run")
+	threshold := fs.Float64("threshold", 0.2, "allowed slowdown against the baseline (0.2 means 20%)")
+	timeout := fs.Duration("timeout", time.Minute, "time limit of every engine on every instance (0 means none)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res bench [options] <directory>\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Files with the extension .cnf are read in DIMACS format,\n")
+		fmt.Fprintf(os.Stderr, "every other file in clause syntax (one clause per line).\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one directory")
+	}
+	selected := solver.Engines()
+	if *engines != "" {
+		selected = nil
+		for _, name := range strings.Split(*engines, ",") {
+			e, ok := solver.Lookup(strings.TrimSpace(name))
+			if !ok {
+				return fmt.Errorf("unknown engine: %q", name)
+			}
+			selected = append(selected, e)
+		}
+	}
+	measurements, err := bench.Run(fs.Arg(0), selected, *timeout)
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "csv":
+		err = bench.WriteCSV(os.Stdout, measurements)
+	case "json":
+		err = bench.WriteJSON(os.Stdout, measurements)
+	default:
+		return fmt.Errorf("unknown format: %q", *format)
+	}
+	if err != nil || *baseline == "" {
+		return err
+	}
+	f, err := os.Open(*baseline)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	saved, err := bench.ReadJSON(f)
+	if err != nil {
+		return fmt.Errorf("reading baseline: %v", err)
+	}
+	regressions := bench.Compare(measurements, saved, *threshold)
+	for _, r := range regressions {
+		fmt.Fprintf(os.Stderr, "regression: %s (%s): %s\n", r.Current.Instance, r.Current.Engine, r.Reason)
+	}
+	if len(regressions) > 0 {
+		return fmt.Errorf("%d regressions against %s", len(regressions), *baseline)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thxrsxm/res/internal/instance"
+	"github.com/thxrsxm/res/internal/proof"
+)
+
+// runCheckProof checks a hand-written resolution proof against the input clauses
+// and reports the first invalid line.
+func runCheckProof(args []string) error {
+	fs := flag.NewFlagSet("check-proof", flag.ExitOnError)
+	proofPath := fs.String("proof", "", "read the proof from `file` instead of standard input")
+	inputPath := fs.String("input", "", "read further input clauses from `file`")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res check-proof [--proof file] [--input file] [--] <clause1> <clause2> ...\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Proof lines:\n")
+		fmt.Fprintf(os.Stderr, "  n: clause           a premise, which must be an input clause\n")
+		fmt.Fprintf(os.Stderr, "  n: clause from i,j  the resolvent of the clauses of lines i and j\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	inputs, err := parseClauses(fs.Args())
+	if err != nil {
+		return err
+	}
+	if *inputPath != "" {
+		loaded, err := instance.Load(*inputPath)
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, loaded...)
+	}
+	r := os.Stdin
+	if *proofPath != "" {
+		f, err := os.Open(*proofPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	lines, err := proof.Read(r)
+	if err != nil {
+		return fmt.Errorf("invalid proof: %v", err)
+	}
+	if err := proof.Check(inputs, lines); err != nil {
+		return fmt.Errorf("invalid proof: %v", err)
+	}
+	fmt.Printf("valid proof: %d lines derive the empty clause\n", len(lines))
+	return nil
+}
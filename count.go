@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thxrsxm/res/internal/count"
+)
+
+// runCount prints the exact number of satisfying assignments of the clause set.
+func runCount(args []string) error {
+	fs := flag.NewFlagSet("count", flag.ExitOnError)
+	project := fs.String("project", "", "only count the `variables` of a clause, e.g. A,B,C")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res count [options] [--] <clause1> <clause2> ...\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	set, err := parseClauses(fs.Args())
+	if err != nil {
+		return err
+	}
+	vars, err := parseProjection(*project)
+	if err != nil {
+		return err
+	}
+	fmt.Println(count.Count(set, vars))
+	return nil
+}
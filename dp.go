@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/dp"
+)
+
+// runDP runs the Davis–Putnam procedure and prints how many clauses
+// were added and removed for every eliminated variable.
+func runDP(args []string) error {
+	fs := flag.NewFlagSet("dp", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res dp [--] <clause1> <clause2> ...\n")
+	}
+	fs.Parse(args)
+	set, err := parseClauses(fs.Args())
+	if err != nil {
+		return err
+	}
+	result, steps := dp.Solve(set, nil, nil)
+	fmt.Printf("start: %d clauses\n", len(set))
+	for _, s := range steps {
+		fmt.Printf("eliminate %s: +%d -%d -> %d clauses\n",
+			clause.Lit2Str(s.Variable), s.Added, s.Removed, s.Size)
+	}
+	printResult(result)
+	return nil
+}
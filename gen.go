@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/dimacs"
+	"github.com/thxrsxm/res/internal/gen"
+)
+
+// runGen generates a clause set of the given family and prints it
+// in clause syntax (one clause per line) or in DIMACS format.
+func runGen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	k := fs.Int("k", 3, "number of literals per clause (random, planted)")
+	n := fs.Int("n", 10, "number of variables (random, planted, parity) or holes (pigeonhole)")
+	m := fs.Int("m", 42, "number of clauses (random, planted)")
+	seed := fs.Int64("seed", 1, "seed of the random number generator")
+	format := fs.String("format", "clauses", "output `format`: clauses or dimacs")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res gen <family> [options]\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Families:\n")
+		fmt.Fprintf(os.Stderr, "  random      Uniform random k-SAT\n")
+		fmt.Fprintf(os.Stderr, "  planted     Random k-SAT satisfied by a hidden assignment\n")
+		fmt.Fprintf(os.Stderr, "  pigeonhole  n+1 pigeons in n holes (unsatisfiable)\n")
+		fmt.Fprintf(os.Stderr, "  parity      Two parity chains over n variables with different results (unsatisfiable)\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return fmt.Errorf("missing family")
+	}
+	family := args[0]
+	fs.Parse(args[1:])
+	var set []clause.Clause
+	var err error
+	switch family {
+	case "random":
+		set, err = gen.Random(*k, *n, *m, *seed)
+	case "planted":
+		set, _, err = gen.Planted(*k, *n, *m, *seed)
+	case "pigeonhole":
+		set, err = gen.Pigeonhole(*n)
+	case "parity":
+		set, err = gen.Parity(*n, *seed)
+	default:
+		return fmt.Errorf("unknown family: %q", family)
+	}
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "clauses":
+		for i := range set {
+			fmt.Println(formatClause(set[i]))
+		}
+	case "dimacs":
+		return dimacs.Write(os.Stdout, set)
+	default:
+		return fmt.Errorf("unknown format: %q", *format)
+	}
+	return nil
+}
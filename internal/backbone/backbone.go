@@ -0,0 +1,77 @@
+// Package backbone computes the backbone of a clause set: the literals that are true in every model.
+//
+// The computation asks an incremental solver one question per candidate literal instead of
+// enumerating models. The literals of a first model are the candidates. A candidate l is in the
+// backbone if the clauses are unsatisfiable under the assumption -l. Otherwise the answer is a
+// model in which l is false, and every candidate that model makes false is dropped as well.
+package backbone
+
+import (
+	"context"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/incremental"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+// Result is the backbone of a clause set.
+type Result struct {
+	// Unsatisfiable is true if the clause set has no model, so it has no backbone.
+	Unsatisfiable bool
+	// Forced lists the literals true in every model sorted by variable.
+	Forced []clause.Literal
+	// Unforced lists the variables that are true in some models and false in others, sorted.
+	// They are not free: the clauses may still constrain them together, as in A,B and -A,-B.
+	Unforced []clause.Literal
+	// Queries is the number of solver calls, the first one included.
+	Queries int
+}
+
+// Compute computes the backbone of the clause set. It returns ctx.Err() if the context is done
+// before the backbone is known. If st is not nil, the work of the solver is recorded.
+func Compute(ctx context.Context, set []clause.Clause, st *stats.Stats) (Result, error) {
+	s := incremental.New()
+	s.Add(set...)
+	r, err := s.Solve(ctx, nil, st)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Queries: 1}
+	if r.Unsatisfiable {
+		result.Unsatisfiable = true
+		return result, nil
+	}
+	candidates := map[clause.Literal]bool{}
+	for _, l := range r.Model {
+		candidates[l] = true
+	}
+	for _, v := range clause.Variables(set) {
+		l := v
+		if !candidates[l] {
+			l = -v
+		}
+		if !candidates[l] {
+			// Dropped by an earlier model
+			result.Unforced = append(result.Unforced, v)
+			continue
+		}
+		r, err := s.Solve(ctx, []clause.Literal{-l}, st)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Queries++
+		if r.Unsatisfiable {
+			result.Forced = append(result.Forced, l)
+			// The unit spares later queries from deriving l again
+			unit := clause.New()
+			unit.Insert(l)
+			s.Add(*unit)
+			continue
+		}
+		result.Unforced = append(result.Unforced, v)
+		for _, k := range r.Model {
+			delete(candidates, -k)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package backbone
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+	"github.com/thxrsxm/res/internal/gen"
+)
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		forced   []clause.Literal
+		unforced []clause.Literal
+	}{
+		{"empty clause set", []string{}, nil, nil},
+		{"units", []string{"A", "-B"}, []clause.Literal{1, -2}, nil},
+		{"implied", []string{"A,B", "A,-B", "-A,C,D"}, []clause.Literal{1}, []clause.Literal{2, 3, 4}},
+		{"chain", []string{"-A,B", "-B,C", "A,C", "-C,D"}, []clause.Literal{3, 4}, []clause.Literal{1, 2}},
+		{"no backbone", []string{"A,B", "-A,-B"}, nil, []clause.Literal{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Compute(context.Background(), clausetest.ParseSet(t, tt.clauses...), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Unsatisfiable {
+				t.Fatalf("Compute(%v) is unsatisfiable", tt.clauses)
+			}
+			if !reflect.DeepEqual(result.Forced, tt.forced) || !reflect.DeepEqual(result.Unforced, tt.unforced) {
+				t.Errorf("Compute(%v) = forced %v, unforced %v; want forced %v, unforced %v", tt.clauses, result.Forced, result.Unforced, tt.forced, tt.unforced)
+			}
+		})
+	}
+}
+
+func TestComputeUnsatisfiable(t *testing.T) {
+	result, err := Compute(context.Background(), clausetest.ParseSet(t, "A,B", "-A", "-B"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Unsatisfiable || result.Forced != nil || result.Unforced != nil {
+		t.Errorf("Compute() = %+v; want unsatisfiable without literals", result)
+	}
+}
+
+func TestComputeRandom(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		set, err := gen.Random(3, 6, 10+int(seed%12), seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := Compute(context.Background(), set, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// A literal is forced if no model makes it false
+		vars := clause.Variables(set)
+		values := map[clause.Literal]map[bool]bool{}
+		for _, v := range vars {
+			values[v] = map[bool]bool{}
+		}
+		clause.Assignments(vars, func(assignment map[clause.Literal]bool) bool {
+			for i := range set {
+				if !set[i].Eval(assignment) {
+					return true
+				}
+			}
+			for _, v := range vars {
+				values[v][assignment[v]] = true
+			}
+			return true
+		})
+		unsat, _ := clause.BruteForce(set)
+		if result.Unsatisfiable != unsat {
+			t.Fatalf("seed %d: Compute().Unsatisfiable = %v; want %v", seed, result.Unsatisfiable, unsat)
+		}
+		if unsat {
+			continue
+		}
+		var forced, unforced []clause.Literal
+		for _, v := range vars {
+			switch {
+			case len(values[v]) == 2:
+				unforced = append(unforced, v)
+			case values[v][true]:
+				forced = append(forced, v)
+			default:
+				forced = append(forced, -v)
+			}
+		}
+		if !reflect.DeepEqual(result.Forced, forced) || !reflect.DeepEqual(result.Unforced, unforced) {
+			t.Errorf("seed %d: Compute() = forced %v, unforced %v; want forced %v, unforced %v", seed, result.Forced, result.Unforced, forced, unforced)
+		}
+		if result.Queries > len(vars)+1 {
+			t.Errorf("seed %d: Compute() took %d queries for %d variables", seed, result.Queries, len(vars))
+		}
+	}
+}
+
+func TestComputeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Compute(ctx, clausetest.ParseSet(t, "A,B"), nil); err != context.Canceled {
+		t.Errorf("Compute with a cancelled context returned %v; want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,240 @@
+// Package bench measures engines on a directory of instances and compares the results with a baseline.
+package bench
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/instance"
+	"github.com/thxrsxm/res/internal/solver"
+)
+
+// NoiseFloor is the shortest baseline duration that is compared.
+// Faster runs are dominated by measurement noise and never count as regressions.
+const NoiseFloor = time.Millisecond
+
+// MemoryFloor is the smallest baseline allocation in bytes that is compared.
+// Smaller allocations vary with the state of the runtime and never count as regressions.
+const MemoryFloor = 64 << 10
+
+// Measurement is the result of running one engine on one instance.
+type Measurement struct {
+	// Instance is the file name of the instance.
+	Instance string `json:"instance"`
+	// Engine is the name of the engine.
+	Engine string `json:"engine"`
+	// Variables is the number of variables of the instance.
+	Variables int `json:"variables"`
+	// Clauses is the number of clauses of the instance.
+	Clauses int `json:"clauses"`
+	// PeakClauses is the largest number of clauses the engine held at once.
+	PeakClauses int `json:"peak_clauses"`
+	// Resolvents is the number of resolvents the engine derived.
+	Resolvents int `json:"resolvents"`
+	// Unsatisfiable is the verdict of the engine. It is false if the engine timed out.
+	Unsatisfiable bool `json:"unsatisfiable"`
+	// TimedOut is true if the engine gave no verdict within the time limit.
+	TimedOut bool `json:"timed_out"`
+	// Duration is the time the engine took.
+	Duration time.Duration `json:"duration_ns"`
+	// Allocated is the number of bytes the engine allocated.
+	Allocated uint64 `json:"allocated_bytes"`
+}
+
+// Regression describes a measurement that got worse compared to the baseline.
+type Regression struct {
+	Current  Measurement
+	Baseline Measurement
+	// Reason explains the regression.
+	Reason string
+}
+
+// Run loads every file of the directory (see instance.Load) and solves it with every applicable engine.
+// Subdirectories and hidden files are skipped. Measurements are ordered by file name, then by engine.
+// If timeout is positive, every engine is cancelled after that time and its measurement is marked
+// as timed out. It fails on the first engine that returns any other error, so no measurement
+// records a failed run.
+func Run(dir string, engines []solver.Engine, timeout time.Duration) ([]Measurement, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	result := []Measurement{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		set, err := instance.Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", entry.Name(), err)
+		}
+		for _, e := range engines {
+			if !e.Applies(set) {
+				continue
+			}
+			m, err := measure(e, set, timeout)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %v", entry.Name(), e.Name, err)
+			}
+			m.Instance = entry.Name()
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// measure solves the clause set with the engine and records time, allocated memory
+// and the clause counts of the engine's statistics. If timeout is positive, the engine is cancelled
+// after that time and the measurement only records that it timed out. It returns every other error
+// of the engine.
+func measure(e solver.Engine, set []clause.Clause, timeout time.Duration) (Measurement, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	result, err := e.Solve(ctx, set, solver.Options{})
+	duration := time.Since(start)
+	runtime.ReadMemStats(&after)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Measurement{
+			Engine:    e.Name,
+			Variables: len(clause.Variables(set)),
+			Clauses:   len(set),
+			TimedOut:  true,
+			Duration:  duration,
+		}, nil
+	}
+	if err != nil {
+		return Measurement{}, err
+	}
+	return Measurement{
+		Engine:        e.Name,
+		Variables:     len(clause.Variables(set)),
+		Clauses:       len(set),
+		PeakClauses:   result.Stats.PeakClauses,
+		Resolvents:    result.Stats.Resolvents,
+		Unsatisfiable: result.Unsatisfiable,
+		Duration:      duration,
+		Allocated:     after.TotalAlloc - before.TotalAlloc,
+	}, nil
+}
+
+// Compare checks every measurement against the baseline measurement of the same instance and engine.
+// A measurement regresses if it timed out while the baseline did not, if its verdict differs, or if
+// its duration, allocated memory, peak clauses or resolvents exceed the baseline by more than
+// threshold (0.2 means 20%). Baseline durations below NoiseFloor and allocations below MemoryFloor
+// are not compared; the clause counts are exact and always compared.
+// Measurements without a baseline or with a timed out baseline are ignored.
+func Compare(current, baseline []Measurement, threshold float64) []Regression {
+	type key struct{ instance, engine string }
+	base := map[key]Measurement{}
+	for _, m := range baseline {
+		base[key{m.Instance, m.Engine}] = m
+	}
+	result := []Regression{}
+	for _, m := range current {
+		b, ok := base[key{m.Instance, m.Engine}]
+		if !ok || b.TimedOut {
+			continue
+		}
+		if m.TimedOut {
+			result = append(result, Regression{m, b, "timed out"})
+			continue
+		}
+		if m.Unsatisfiable != b.Unsatisfiable {
+			result = append(result, Regression{m, b, "verdict changed"})
+			continue
+		}
+		reasons := []string{}
+		if b.Duration >= NoiseFloor && exceeds(float64(m.Duration), float64(b.Duration), threshold) {
+			reasons = append(reasons, fmt.Sprintf("time %v -> %v%s", b.Duration, m.Duration, increase(float64(m.Duration), float64(b.Duration))))
+		}
+		if b.Allocated >= MemoryFloor && exceeds(float64(m.Allocated), float64(b.Allocated), threshold) {
+			reasons = append(reasons, fmt.Sprintf("memory %d -> %d bytes%s", b.Allocated, m.Allocated, increase(float64(m.Allocated), float64(b.Allocated))))
+		}
+		if exceeds(float64(m.PeakClauses), float64(b.PeakClauses), threshold) {
+			reasons = append(reasons, fmt.Sprintf("peak clauses %d -> %d%s", b.PeakClauses, m.PeakClauses, increase(float64(m.PeakClauses), float64(b.PeakClauses))))
+		}
+		if exceeds(float64(m.Resolvents), float64(b.Resolvents), threshold) {
+			reasons = append(reasons, fmt.Sprintf("resolvents %d -> %d%s", b.Resolvents, m.Resolvents, increase(float64(m.Resolvents), float64(b.Resolvents))))
+		}
+		if len(reasons) > 0 {
+			result = append(result, Regression{m, b, strings.Join(reasons, ", ")})
+		}
+	}
+	return result
+}
+
+// exceeds checks if the current value is larger than the baseline by more than threshold.
+func exceeds(current, baseline, threshold float64) bool {
+	return current > baseline*(1+threshold)
+}
+
+// increase formats the growth from the baseline to the current value, e.g. " (+50%)".
+// It is empty if the baseline is zero.
+func increase(current, baseline float64) string {
+	if baseline == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (+%.0f%%)", 100*(current/baseline-1))
+}
+
+// WriteCSV writes the measurements as CSV with a header line.
+func WriteCSV(w io.Writer, measurements []Measurement) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"instance", "engine", "variables", "clauses", "peak_clauses", "resolvents", "unsatisfiable", "timed_out", "duration_ns", "allocated_bytes"})
+	for _, m := range measurements {
+		// A timed out run has no verdict
+		unsat := strconv.FormatBool(m.Unsatisfiable)
+		if m.TimedOut {
+			unsat = ""
+		}
+		cw.Write([]string{
+			m.Instance,
+			m.Engine,
+			strconv.Itoa(m.Variables),
+			strconv.Itoa(m.Clauses),
+			strconv.Itoa(m.PeakClauses),
+			strconv.Itoa(m.Resolvents),
+			unsat,
+			strconv.FormatBool(m.TimedOut),
+			strconv.FormatInt(int64(m.Duration), 10),
+			strconv.FormatUint(m.Allocated, 10),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the measurements as an indented JSON array.
+func WriteJSON(w io.Writer, measurements []Measurement) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(measurements)
+}
+
+// ReadJSON reads measurements written by WriteJSON, e.g. a saved baseline.
+func ReadJSON(r io.Reader) ([]Measurement, error) {
+	measurements := []Measurement{}
+	if err := json.NewDecoder(r).Decode(&measurements); err != nil {
+		return nil, err
+	}
+	return measurements, nil
+}
@@ -0,0 +1,215 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+	"github.com/thxrsxm/res/internal/solver"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"horn.txt":  "A\n-A,B\n-B\n",
+		"sat.cnf":   "p cnf 3 2\n1 2 3 0\n-1 -2 -3 0\n",
+		".hidden":   "invalid",
+		"unsat.txt": "A,B,C\nA,B,-C\nA,-B\n-A,B\n-A,-B\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	horn, _ := solver.Lookup(solver.EngineHorn)
+	dpll, _ := solver.Lookup(solver.EngineDPLL)
+	result, err := Run(dir, []solver.Engine{horn, dpll}, 0)
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	expected := []struct {
+		instance, engine string
+		unsat            bool
+	}{
+		{"horn.txt", solver.EngineHorn, true},
+		{"horn.txt", solver.EngineDPLL, true},
+		{"sat.cnf", solver.EngineDPLL, false},
+		{"unsat.txt", solver.EngineDPLL, true},
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("Run() = %+v; want %d measurements", result, len(expected))
+	}
+	for i, e := range expected {
+		m := result[i]
+		if m.Instance != e.instance || m.Engine != e.engine || m.Unsatisfiable != e.unsat {
+			t.Errorf("measurement %d = %s/%s/%v; want %s/%s/%v", i, m.Instance, m.Engine, m.Unsatisfiable, e.instance, e.engine, e.unsat)
+		}
+	}
+	if result[2].Variables != 3 || result[2].Clauses != 2 {
+		t.Errorf("sat.cnf has %d variables and %d clauses; want 3 and 2", result[2].Variables, result[2].Clauses)
+	}
+}
+
+func TestMeasureStats(t *testing.T) {
+	resolution, _ := solver.Lookup(solver.EngineResolution)
+	m, err := measure(resolution, clausetest.ParseSet(t, "A,B", "A,-B", "-A,B", "-A,-B"), 0)
+	if err != nil {
+		t.Fatalf("measure() failed: %v", err)
+	}
+	if !m.Unsatisfiable {
+		t.Fatalf("measure() = %+v; want unsatisfiable", m)
+	}
+	if m.Resolvents == 0 || m.PeakClauses <= m.Clauses {
+		t.Errorf("measure() recorded %d resolvents and %d peak clauses; want resolvents and more than %d peak clauses", m.Resolvents, m.PeakClauses, m.Clauses)
+	}
+}
+
+func TestRunInvalidInstance(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bad.txt"), []byte("A,1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(dir, solver.Engines(), 0); err == nil {
+		t.Errorf("Run() succeeded; want error for invalid instance")
+	}
+	if _, err := Run(filepath.Join(dir, "missing"), solver.Engines(), 0); err == nil {
+		t.Errorf("Run() succeeded; want error for missing directory")
+	}
+}
+
+func TestRunEngineError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("A\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	failing := solver.Engine{
+		Name:    "failing",
+		Applies: func([]clause.Clause) bool { return true },
+		Solve: func(context.Context, []clause.Clause, solver.Options) (solver.Result, error) {
+			return solver.Result{}, errors.New("rejected")
+		},
+	}
+	result, err := Run(dir, []solver.Engine{failing}, 0)
+	if err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("Run() = %+v, %v; want the error of the engine", result, err)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("A\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	blocking := solver.Engine{
+		Name:    "blocking",
+		Applies: func([]clause.Clause) bool { return true },
+		Solve: func(ctx context.Context, _ []clause.Clause, _ solver.Options) (solver.Result, error) {
+			<-ctx.Done()
+			return solver.Result{}, ctx.Err()
+		},
+	}
+	result, err := Run(dir, []solver.Engine{blocking}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if len(result) != 1 || !result[0].TimedOut || result[0].Unsatisfiable {
+		t.Errorf("Run() = %+v; want one timed out measurement without verdict", result)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	m := func(instance string, unsat bool, d time.Duration) Measurement {
+		return Measurement{Instance: instance, Engine: "dpll", Unsatisfiable: unsat, Duration: d}
+	}
+	baseline := []Measurement{
+		m("fast.txt", true, 100*time.Microsecond),
+		m("same.txt", true, 10*time.Millisecond),
+		m("slower.txt", false, 10*time.Millisecond),
+		m("verdict.txt", false, 10*time.Millisecond),
+		m("timeout.txt", true, 10*time.Millisecond),
+		{Instance: "unknown.txt", Engine: "dpll", TimedOut: true, Duration: time.Second},
+		{Instance: "counts.txt", Engine: "dpll", PeakClauses: 10, Resolvents: 0, Allocated: 1 << 20},
+		{Instance: "small.txt", Engine: "dpll", Allocated: 1 << 10},
+	}
+	current := []Measurement{
+		m("fast.txt", true, 900*time.Microsecond),
+		m("same.txt", true, 11*time.Millisecond),
+		m("slower.txt", false, 15*time.Millisecond),
+		m("verdict.txt", true, 10*time.Millisecond),
+		m("new.txt", true, time.Second),
+		{Instance: "timeout.txt", Engine: "dpll", TimedOut: true, Duration: time.Second},
+		m("unknown.txt", false, time.Millisecond),
+		{Instance: "counts.txt", Engine: "dpll", PeakClauses: 15, Resolvents: 3, Allocated: 2 << 20},
+		{Instance: "small.txt", Engine: "dpll", Allocated: 1 << 15},
+	}
+	result := Compare(current, baseline, 0.2)
+	if len(result) != 4 {
+		t.Fatalf("Compare() = %+v; want 4 regressions", result)
+	}
+	if result[0].Current.Instance != "slower.txt" || !strings.Contains(result[0].Reason, "+50%") {
+		t.Errorf("first regression = %s: %s; want slower.txt with +50%%", result[0].Current.Instance, result[0].Reason)
+	}
+	if result[1].Current.Instance != "verdict.txt" || result[1].Reason != "verdict changed" {
+		t.Errorf("second regression = %s: %s; want verdict.txt with changed verdict", result[1].Current.Instance, result[1].Reason)
+	}
+	if result[2].Current.Instance != "timeout.txt" || result[2].Reason != "timed out" {
+		t.Errorf("third regression = %s: %s; want timeout.txt timed out", result[2].Current.Instance, result[2].Reason)
+	}
+	expected := "memory 1048576 -> 2097152 bytes (+100%), peak clauses 10 -> 15 (+50%), resolvents 0 -> 3"
+	if result[3].Current.Instance != "counts.txt" || result[3].Reason != expected {
+		t.Errorf("fourth regression = %s: %s; want counts.txt: %s", result[3].Current.Instance, result[3].Reason, expected)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	measurements := []Measurement{
+		{"a.txt", "dp", 2, 3, 5, 2, true, false, 1500, 2048},
+		{"b.txt", "dp", 2, 3, 0, 0, false, true, 60000, 0},
+	}
+	if err := WriteCSV(&buf, measurements); err != nil {
+		t.Fatalf("WriteCSV() failed: %v", err)
+	}
+	expected := "instance,engine,variables,clauses,peak_clauses,resolvents,unsatisfiable,timed_out,duration_ns,allocated_bytes\n" +
+		"a.txt,dp,2,3,5,2,true,false,1500,2048\n" +
+		"b.txt,dp,2,3,0,0,,true,60000,0\n"
+	if buf.String() != expected {
+		t.Errorf("WriteCSV() = %q; want %q", buf.String(), expected)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	measurements := []Measurement{
+		{"a.txt", "dp", 2, 3, 5, 2, true, false, 1500, 2048},
+		{"b.cnf", "dpll", 5, 8, 0, 0, false, true, 42, 0},
+	}
+	if err := WriteJSON(&buf, measurements); err != nil {
+		t.Fatalf("WriteJSON() failed: %v", err)
+	}
+	result, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSON() failed: %v", err)
+	}
+	if len(result) != len(measurements) {
+		t.Fatalf("ReadJSON() = %+v; want %+v", result, measurements)
+	}
+	for i := range result {
+		if result[i] != measurements[i] {
+			t.Errorf("ReadJSON()[%d] = %+v; want %+v", i, result[i], measurements[i])
+		}
+	}
+	if _, err := ReadJSON(strings.NewReader("not json")); err == nil {
+		t.Errorf("ReadJSON() succeeded; want error for invalid input")
+	}
+}
@@ -0,0 +1,129 @@
+// Package checker replays resolution refutations to certify that a clause set is unsatisfiable.
+//
+// The checker does not depend on the prover: clauses are plain slices of non-zero ints,
+// where a negative value is the negation of a variable, and every step is checked
+// with its own implementation of the resolution rule.
+package checker
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Step derives a new clause by resolving two earlier clauses.
+type Step struct {
+	// Left and Right are the indices of the parent clauses. The input clauses come first,
+	// step i derives the clause with index len(inputs)+i.
+	Left, Right int
+	// Pivot is the literal of the left clause whose negation is in the right clause.
+	Pivot int
+	// Resolvent is the derived clause.
+	Resolvent []int
+}
+
+// Check replays a refutation of the input clauses. Every step must resolve two earlier
+// clauses on its pivot and derive exactly its resolvent, and the last step must derive
+// the empty clause. A refutation without steps is only valid if an input clause is empty.
+// Returns an error describing the first invalid step.
+func Check(inputs [][]int, steps []Step) error {
+	clauses := make([]map[int]bool, 0, len(inputs)+len(steps))
+	for i, c := range inputs {
+		set, err := toSet(c)
+		if err != nil {
+			return fmt.Errorf("input clause %d: %v", i, err)
+		}
+		clauses = append(clauses, set)
+	}
+	if len(steps) == 0 {
+		for _, c := range clauses {
+			if len(c) == 0 {
+				return nil
+			}
+		}
+		return fmt.Errorf("no steps and no empty input clause")
+	}
+	for i, step := range steps {
+		if err := check(clauses, step); err != nil {
+			return fmt.Errorf("step %d: %v", i, err)
+		}
+		resolvent, _ := toSet(step.Resolvent)
+		clauses = append(clauses, resolvent)
+	}
+	if last := steps[len(steps)-1]; len(last.Resolvent) != 0 {
+		return fmt.Errorf("last step derives %v, not the empty clause", last.Resolvent)
+	}
+	return nil
+}
+
+// check verifies a single step against the clauses derived so far.
+func check(clauses []map[int]bool, step Step) error {
+	for _, parent := range []int{step.Left, step.Right} {
+		if parent < 0 || parent >= len(clauses) {
+			return fmt.Errorf("parent %d is not an earlier clause", parent)
+		}
+	}
+	left, right := clauses[step.Left], clauses[step.Right]
+	if step.Pivot == 0 || !left[step.Pivot] || !right[-step.Pivot] {
+		return fmt.Errorf("pivot %d is not in clause %d with its negation in clause %d", step.Pivot, step.Left, step.Right)
+	}
+	expected := map[int]bool{}
+	for l := range left {
+		if l != step.Pivot {
+			expected[l] = true
+		}
+	}
+	for l := range right {
+		if l != -step.Pivot {
+			expected[l] = true
+		}
+	}
+	for l := range expected {
+		if expected[-l] {
+			return fmt.Errorf("resolvent of clauses %d and %d is a tautology", step.Left, step.Right)
+		}
+	}
+	resolvent, err := toSet(step.Resolvent)
+	if err != nil {
+		return err
+	}
+	if !equal(expected, resolvent) {
+		return fmt.Errorf("resolvent of clauses %d and %d on %d is %v, not %v",
+			step.Left, step.Right, step.Pivot, sorted(expected), step.Resolvent)
+	}
+	return nil
+}
+
+// toSet converts a clause to a set of literals. Zero is not a literal.
+func toSet(c []int) (map[int]bool, error) {
+	set := make(map[int]bool, len(c))
+	for _, l := range c {
+		if l == 0 {
+			return nil, fmt.Errorf("0 is not a literal")
+		}
+		set[l] = true
+	}
+	return set, nil
+}
+
+// equal checks if two sets of literals contain the same literals.
+func equal(a, b map[int]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for l := range a {
+		if !b[l] {
+			return false
+		}
+	}
+	return true
+}
+
+// sorted returns the literals of a set in ascending order.
+func sorted(set map[int]bool) []int {
+	result := make([]int, 0, len(set))
+	for l := range set {
+		result = append(result, l)
+	}
+	sort.Ints(result)
+	return result
+}
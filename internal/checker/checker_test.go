@@ -0,0 +1,46 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	// {A, B}, {-A, B}, {A, -B}, {-A, -B}
+	inputs := [][]int{{1, 2}, {-1, 2}, {1, -2}, {-1, -2}}
+	valid := []Step{
+		{Left: 0, Right: 1, Pivot: 1, Resolvent: []int{2}},
+		{Left: 2, Right: 3, Pivot: 1, Resolvent: []int{-2}},
+		{Left: 4, Right: 5, Pivot: 2, Resolvent: []int{}},
+	}
+	tests := []struct {
+		name     string
+		inputs   [][]int
+		steps    []Step
+		expected string
+	}{
+		{"valid", inputs, valid, ""},
+		{"empty input clause", [][]int{{1}, {}}, nil, ""},
+		{"no steps", inputs, nil, "no steps and no empty input clause"},
+		{"zero literal", [][]int{{1, 0}}, nil, "input clause 0: 0 is not a literal"},
+		{"later parent", inputs, []Step{{Left: 0, Right: 4, Pivot: 2, Resolvent: []int{}}}, "step 0: parent 4 is not an earlier clause"},
+		{"pivot not in left", inputs, []Step{{Left: 1, Right: 0, Pivot: 1, Resolvent: []int{2}}}, "step 0: pivot 1 is not in clause 1"},
+		{"tautology", inputs, []Step{{Left: 0, Right: 3, Pivot: 1, Resolvent: []int{2, -2}}}, "step 0: resolvent of clauses 0 and 3 is a tautology"},
+		{"wrong resolvent", inputs, []Step{{Left: 0, Right: 1, Pivot: 1, Resolvent: []int{1, 2}}}, "step 0: resolvent of clauses 0 and 1 on 1 is [2], not [1 2]"},
+		{"no empty clause", inputs, valid[:2], "last step derives [-2], not the empty clause"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.inputs, tt.steps)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Check() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.expected) {
+				t.Errorf("Check() = %v; want %q", err, tt.expected)
+			}
+		})
+	}
+}
@@ -2,9 +2,12 @@
 package clause
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 
+	"github.com/thxrsxm/res/internal/stats"
 	"github.com/thxrsxm/res/internal/utils"
 )
 
@@ -15,10 +18,13 @@ type Literal int
 const ErrorLiteral Literal = 0
 
 // lit2Str maps literal values to their string representations.
-var lit2Str = []byte{
+var lit2Str = [...]byte{
 	'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z',
 }
 
+// MaxVariable is the largest variable that can be represented (Z).
+const MaxVariable = Literal(len(lit2Str))
+
 // Lit2Str converts a Literal to its string representation.
 // For positive literals, returns the letter (e.g., 1 → "A").
 // For negative literals, returns the letter with a minus prefix (e.g., -1 → "-A").
@@ -38,6 +44,11 @@ func Lit2Str(l Literal) string {
 	return s + string(lit2Str[l-1])
 }
 
+// Var returns the variable of the literal, i.e. the literal without its sign.
+func (l Literal) Var() Literal {
+	return Literal(utils.Abs(int(l)))
+}
+
 // Str2Lit converts a string to a Literal.
 // Accepts strings like "A", "a", "-A", "-a".
 // Returns ErrorLiteral for invalid input.
@@ -111,6 +122,20 @@ func (c *Clause) Size() int {
 	return len(c.literals)
 }
 
+// Literals returns the literals of the clause sorted by their absolute value.
+func (c *Clause) Literals() []Literal {
+	values := make([]int, 0, len(c.literals))
+	for l := range c.literals {
+		values = append(values, int(l))
+	}
+	utils.UnsignedSort(values)
+	result := make([]Literal, len(values))
+	for i, v := range values {
+		result[i] = Literal(v)
+	}
+	return result
+}
+
 // Resolve applies the resolution rule between this clause and another.
 // Resolution rule: If two clauses contain complementary literals (A and ¬A),
 // those literals can be removed and the remaining literals form a new clause (the resolvent).
@@ -136,6 +161,21 @@ func (c *Clause) Resolve(other Clause) (*Clause, bool) {
 	return temp, found
 }
 
+// Pivot returns the literal of the clause whose negation is in the other clause.
+// It returns false if there is no such literal or more than one, in which case Resolve fails as well.
+func (c *Clause) Pivot(other Clause) (Literal, bool) {
+	pivot := ErrorLiteral
+	for l := range c.literals {
+		if other.Contains(-l) {
+			if pivot != ErrorLiteral {
+				return ErrorLiteral, false
+			}
+			pivot = l
+		}
+	}
+	return pivot, pivot != ErrorLiteral
+}
+
 // Copy creates and returns a deep copy of the clause.
 func (c *Clause) Copy() *Clause {
 	temp := New()
@@ -163,14 +203,10 @@ func (c *Clause) Equals(other Clause) bool {
 // Literals are sorted by their absolute value for consistent output.
 // Example: A clause containing literals B, -A, and C would be represented as "{-A, B, C}".
 func (c *Clause) String() string {
-	values := make([]int, 0, len(c.literals))
-	for l := range c.literals {
-		values = append(values, int(l))
-	}
-	utils.UnsignedSort(values)
+	values := c.Literals()
 	s := "{"
 	for i, l := range values {
-		s += Lit2Str(Literal(l))
+		s += Lit2Str(l)
 		if i < len(values)-1 {
 			s += ", "
 		}
@@ -216,6 +252,96 @@ func Parse(s string) (*Clause, error) {
 	return c, nil
 }
 
+// Variables returns the variables occurring in a set of clauses in ascending order.
+func Variables(set []Clause) []Literal {
+	seen := make(map[Literal]struct{})
+	values := []int{}
+	for i := range set {
+		for l := range set[i].literals {
+			if _, ok := seen[l.Var()]; !ok {
+				seen[l.Var()] = struct{}{}
+				values = append(values, int(l.Var()))
+			}
+		}
+	}
+	utils.UnsignedSort(values)
+	result := make([]Literal, len(values))
+	for i, v := range values {
+		result[i] = Literal(v)
+	}
+	return result
+}
+
+// Eval returns the value of the clause under an assignment of its variables.
+// Variables missing from the assignment are false.
+// The clause is true if at least one of its literals is true, so the empty clause is always false.
+func (c *Clause) Eval(assignment map[Literal]bool) bool {
+	for l := range c.literals {
+		if assignment[l.Var()] == (l > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// Assignments calls fn for every assignment of the given variables, 2^len(vars) in total.
+// Assignments are generated in binary counting order with the last variable changing fastest,
+// starting with all variables false. The map passed to fn is reused between calls.
+// Enumeration stops early if fn returns false.
+func Assignments(vars []Literal, fn func(assignment map[Literal]bool) bool) {
+	assignment := make(map[Literal]bool, len(vars))
+	for n := 0; n < 1<<len(vars); n++ {
+		for i, v := range vars {
+			assignment[v] = n&(1<<(len(vars)-1-i)) != 0
+		}
+		if !fn(assignment) {
+			return
+		}
+	}
+}
+
+// BruteForce checks if a set of clauses is unsatisfiable by evaluating it under every assignment of its variables.
+// It returns true if the clause set is unsatisfiable, otherwise false together with the first
+// satisfying assignment, given as the true literals sorted by variable.
+//
+// The running time grows exponentially with the number of variables,
+// so it is meant as a reference for small clause sets.
+func BruteForce(set []Clause) (bool, []Literal) {
+	unsat, model, _ := BruteForceContext(context.Background(), set)
+	return unsat, model
+}
+
+// BruteForceContext works like BruteForce, but stops when the context is done and returns its error.
+// The context is checked before every assignment.
+func BruteForceContext(ctx context.Context, set []Clause) (bool, []Literal, error) {
+	vars := Variables(set)
+	var model []Literal
+	var err error
+	Assignments(vars, func(assignment map[Literal]bool) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		for i := range set {
+			if !set[i].Eval(assignment) {
+				return true
+			}
+		}
+		model = make([]Literal, len(vars))
+		for i, v := range vars {
+			if assignment[v] {
+				model[i] = v
+			} else {
+				model[i] = -v
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return false, nil, err
+	}
+	return model == nil, model, nil
+}
+
 // Res checks if a set of clauses is unsatisfiable using the resolution method.
 // It returns true if the clause set is unsatisfiable (contains a contradiction),
 // false if it is satisfiable (no contradiction found).
@@ -230,27 +356,109 @@ func Parse(s string) (*Clause, error) {
 //   - set: The set of clauses to check
 //   - index: The starting index for resolution (used internally for recursion)
 func Res(set []Clause, index int) bool {
+	return resSteps(set, index, nil)
+}
+
+// resSteps implements Res. If steps is not nil, the step deriving every clause appended to the set
+// is added to it, so the tests can certify the refutation of the very run they check.
+func resSteps(set []Clause, index int, steps *[]Step) bool {
+	return (&saturation{ctx: context.Background(), st: &stats.Stats{}, steps: steps}).res(set, index)
+}
+
+// ResStats works like Res starting at index 0 and records its work in st:
+// rounds, resolvents, duplicates, tautologies, peak clause count and largest clause.
+func ResStats(set []Clause, st *stats.Stats) bool {
+	return ResParallel(set, 1, st)
+}
+
+// ResParallel works like ResStats, but resolves the pairs of every clause on up to workers
+// goroutines. The resolvents are added in the same order as by ResStats, so the verdict,
+// the statistics and the refutations of RefuteParallel are the same for every worker count.
+func ResParallel(set []Clause, workers int, st *stats.Stats) bool {
+	return resParallelSteps(set, workers, st, nil)
+}
+
+// resParallelSteps implements ResParallel and records the derived clauses in steps like resSteps.
+func resParallelSteps(set []Clause, workers int, st *stats.Stats, steps *[]Step) bool {
+	st.Clauses(len(set))
+	for i := range set {
+		st.ClauseSize(set[i].Size())
+	}
+	return (&saturation{ctx: context.Background(), st: st, steps: steps, workers: workers}).res(set, 0)
+}
+
+// saturation holds the configuration of a run of res.
+type saturation struct {
+	// ctx stops the search when it is done, err is set to its error then
+	ctx context.Context
+	err error
+	st  *stats.Stats
+	// steps is not nil if the step deriving every clause appended to the set is to be recorded
+	steps *[]Step
+	// workers is the number of goroutines resolving pairs, values below 2 resolve them in place
+	workers int
+}
+
+// candidate is the outcome of resolving a pair of clauses, computed ahead of adding it to the set.
+type candidate struct {
+	resolvent *Clause
+	resolved  bool
+	// known is true if the resolvent equals a clause of the set at the time it was computed
+	known bool
+}
+
+// res implements Res, ResParallel and Refute.
+// If the context of the saturation is done, it stops, sets s.err and returns false.
+func (s *saturation) res(set []Clause, index int) bool {
+	st := s.st
+	st.Rounds++
 	size := len(set)
 	for i := len(set) - 1; i >= 0; i-- {
 		if set[i].IsEmpty() {
 			return true
 		}
-		for k := len(set) - 1; k >= index; k-- {
+		if s.err = s.ctx.Err(); s.err != nil {
+			return false
+		}
+		// The pairs of clause i only refer to clauses present before its first resolvent is added
+		last := len(set) - 1
+		candidates := s.resolve(set, i, index, last)
+		for k := last; k >= index; k-- {
 			if i == k {
 				continue
 			}
-			c, resolved := set[i].Resolve(set[k])
+			var c *Clause
+			var resolved, exists bool
+			if candidates != nil {
+				cand := candidates[last-k]
+				c, resolved, exists = cand.resolvent, cand.resolved, cand.known
+			} else {
+				c, resolved = set[i].Resolve(set[k])
+			}
+			if !resolved && c == nil {
+				st.Tautologies++
+			}
 			if resolved && c != nil {
 				// Check if the resolvent is already in the set
-				exists := false
-				for j := range set {
-					if c.Equals(set[j]) {
-						exists = true
-						break
-					}
+				start := 0
+				if candidates != nil {
+					// Only the resolvents added since the candidates were computed are left to check
+					start = last + 1
 				}
-				if !exists {
+				for j := start; j < len(set) && !exists; j++ {
+					exists = c.Equals(set[j])
+				}
+				if exists {
+					st.Duplicates++
+				} else {
 					set = append(set, *c)
+					if s.steps != nil {
+						pivot, _ := set[i].Pivot(set[k])
+						*s.steps = append(*s.steps, Step{Left: i, Right: k, Pivot: pivot, Resolvent: *c})
+					}
+					st.Resolvents++
+					st.Clauses(len(set))
+					st.ClauseSize(c.Size())
 					// If we found an empty clause, return immediately
 					if c.IsEmpty() {
 						return true
@@ -267,5 +475,183 @@ func Res(set []Clause, index int) bool {
 	if size == len(set) {
 		return false
 	}
-	return Res(set, size)
+	return s.res(set, size)
+}
+
+// resolve resolves clause i with the clauses from index to last on the workers of the saturation
+// and checks the resolvents against the clauses up to last. Candidate last-k belongs to clause k.
+// Returns nil if the pairs are to be resolved in place.
+func (s *saturation) resolve(set []Clause, i, index, last int) []candidate {
+	n := last - index + 1
+	if s.workers < 2 || n < 2 {
+		return nil
+	}
+	candidates := make([]candidate, n)
+	workers := min(s.workers, n)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			// Worker w takes every workers-th pair, so long and short clauses are spread evenly
+			for j := w; j < n; j += workers {
+				k := last - j
+				if k == i {
+					continue
+				}
+				c, resolved := set[i].Resolve(set[k])
+				cand := candidate{resolvent: c, resolved: resolved}
+				if resolved && c != nil {
+					cand.known = Index(set[:last+1], *c) >= 0
+				}
+				candidates[j] = cand
+			}
+		}(w)
+	}
+	wg.Wait()
+	return candidates
+}
+
+// Step is a single application of the resolution rule to two clauses of a set.
+type Step struct {
+	// Left and Right are the indices of the resolved clauses in the set.
+	Left, Right int
+	// Pivot is the literal of the left clause whose negation is in the right clause.
+	Pivot Literal
+	// Resolvent is the result of the resolution.
+	Resolvent Clause
+}
+
+// Round returns every resolution step between two clauses of the set of which
+// at least one has an index of at least index, ordered by Right and then Left.
+// Pairs that do not resolve (no complementary literals or a tautology) are skipped.
+//
+// Calling Round with index 0 and then again with the previous length of the set,
+// after adding the new resolvents, saturates the set one round at a time.
+func Round(set []Clause, index int) []Step {
+	steps := []Step{}
+	for k := max(index, 1); k < len(set); k++ {
+		for i := 0; i < k; i++ {
+			pivot, ok := set[i].Pivot(set[k])
+			if !ok {
+				continue
+			}
+			c, _ := set[i].Resolve(set[k])
+			steps = append(steps, Step{Left: i, Right: k, Pivot: pivot, Resolvent: *c})
+		}
+	}
+	return steps
+}
+
+// Refute checks if a set of clauses is unsatisfiable like Res and returns a refutation,
+// the resolution steps that derive the empty clause. It resolves round by round, every round
+// pairing the clauses derived in the previous round with all earlier clauses (see Round),
+// so the refutation is found after the fewest rounds.
+//
+// Clauses are referred to by index: the clauses of the set come first and step i derives
+// the clause with index len(set)+i, so every step only refers to clauses before it.
+// Steps that do not contribute to the empty clause are left out. If the set already
+// contains the empty clause, the refutation has no steps.
+//
+// Returns false and nil if the set is satisfiable. The set itself is not modified.
+// If st is not nil, rounds, resolvents, duplicates, peak clause count and largest clause are recorded.
+func Refute(set []Clause, st *stats.Stats) (bool, []Step) {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	if Index(set, *New()) >= 0 {
+		return true, []Step{}
+	}
+	all := append([]Clause{}, set...)
+	st.Clauses(len(all))
+	for i := range all {
+		st.ClauseSize(all[i].Size())
+	}
+	derived := []Step{}
+	for index := 0; index < len(all); {
+		st.Rounds++
+		size := len(all)
+		for _, step := range Round(all[:size], index) {
+			if Index(all, step.Resolvent) >= 0 {
+				st.Duplicates++
+				continue
+			}
+			all = append(all, step.Resolvent)
+			derived = append(derived, step)
+			st.Resolvents++
+			st.Clauses(len(all))
+			st.ClauseSize(step.Resolvent.Size())
+			if step.Resolvent.IsEmpty() {
+				return true, trim(len(set), derived)
+			}
+		}
+		index = size
+	}
+	return false, nil
+}
+
+// RefuteParallel works like Refute, but returns the refutation found by the search of ResParallel,
+// which the resolution engine runs. The search does not go round by round like Refute, so the
+// refutation may differ from the one of Refute; it is the same for every worker count.
+func RefuteParallel(set []Clause, workers int, st *stats.Stats) (bool, []Step) {
+	unsat, steps, _ := RefuteContext(context.Background(), set, workers, st)
+	return unsat, steps
+}
+
+// RefuteContext works like RefuteParallel, but stops when the context is done and returns its error.
+func RefuteContext(ctx context.Context, set []Clause, workers int, st *stats.Stats) (bool, []Step, error) {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	if Index(set, *New()) >= 0 {
+		return true, []Step{}, nil
+	}
+	derived := []Step{}
+	s := &saturation{ctx: ctx, st: st, steps: &derived, workers: workers}
+	if !s.res(append([]Clause{}, set...), 0) {
+		return false, nil, s.err
+	}
+	return true, trim(len(set), derived), nil
+}
+
+// trim returns the steps the last step depends on, renumbered so that the clause
+// derived by the i-th returned step has index n+i, where n is the number of input clauses.
+func trim(n int, derived []Step) []Step {
+	needed := make([]bool, len(derived))
+	needed[len(derived)-1] = true
+	for i := len(derived) - 1; i >= 0; i-- {
+		if !needed[i] {
+			continue
+		}
+		for _, parent := range []int{derived[i].Left, derived[i].Right} {
+			if parent >= n {
+				needed[parent-n] = true
+			}
+		}
+	}
+	renumbered := map[int]int{}
+	steps := []Step{}
+	for i, step := range derived {
+		if !needed[i] {
+			continue
+		}
+		renumbered[n+i] = n + len(steps)
+		for _, parent := range []*int{&step.Left, &step.Right} {
+			if *parent >= n {
+				*parent = renumbered[*parent]
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// Index returns the index of the first clause of the set equal to c, or -1 if there is none.
+func Index(set []Clause, c Clause) int {
+	for i := range set {
+		if set[i].Equals(c) {
+			return i
+		}
+	}
+	return -1
 }
@@ -1,7 +1,14 @@
 package clause
 
 import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
+
+	"github.com/thxrsxm/res/internal/checker"
+	"github.com/thxrsxm/res/internal/stats"
 )
 
 func TestLit2Str(t *testing.T) {
@@ -63,6 +70,26 @@ func TestStr2Lit(t *testing.T) {
 	}
 }
 
+func TestLiteralVar(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Literal
+		expected Literal
+	}{
+		{"positive", 3, 3},
+		{"negative", -3, 3},
+		{"zero", ErrorLiteral, ErrorLiteral},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.input.Var()
+			if result != tt.expected {
+				t.Errorf("Literal(%d).Var() = %d; want %d", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestClauseString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1340,6 +1367,40 @@ func TestClauseInsert(t *testing.T) {
 	}
 }
 
+func TestClauseLiterals(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Literal
+	}{
+		{"single literal", "A", []Literal{1}},
+		{"sorted by absolute value", "C,-A,B", []Literal{-1, 2, 3}},
+		{"negative literals", "-Z,-M", []Literal{-13, -26}},
+		{"duplicates removed", "A,A,B", []Literal{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+			}
+			result := c.Literals()
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Literals() = %v; want %v", result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("Literals() = %v; want %v", result, tt.expected)
+					return
+				}
+			}
+		})
+	}
+	if l := New().Literals(); len(l) != 0 {
+		t.Errorf("Literals() of empty clause = %v; want []", l)
+	}
+}
+
 func TestClauseResolve(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -1651,6 +1712,436 @@ func TestClauseResolve(t *testing.T) {
 	}
 }
 
+func TestVariables(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		expected []Literal
+	}{
+		{"empty set", []string{}, []Literal{}},
+		{"single clause", []string{"-C,A"}, []Literal{1, 3}},
+		{"shared variables", []string{"A,B", "-A,-B", "B,-D"}, []Literal{1, 2, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := []Clause{}
+			for _, s := range tt.clauses {
+				c, err := Parse(s)
+				if err != nil {
+					t.Fatalf("Parse(%q) failed: %v", s, err)
+				}
+				set = append(set, *c)
+			}
+			result := Variables(set)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Variables() = %v; want %v", result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("Variables() = %v; want %v", result, tt.expected)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestClauseEval(t *testing.T) {
+	tests := []struct {
+		name       string
+		clause     string
+		assignment map[Literal]bool
+		expected   bool
+	}{
+		{"positive literal true", "A", map[Literal]bool{1: true}, true},
+		{"positive literal false", "A", map[Literal]bool{1: false}, false},
+		{"negative literal true", "-A", map[Literal]bool{1: false}, true},
+		{"negative literal false", "-A", map[Literal]bool{1: true}, false},
+		{"missing variable is false", "-B", map[Literal]bool{}, true},
+		{"one true literal", "A,-B,C", map[Literal]bool{1: false, 2: true, 3: true}, true},
+		{"all literals false", "A,-B,C", map[Literal]bool{1: false, 2: true, 3: false}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse(tt.clause)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.clause, err)
+			}
+			result := c.Eval(tt.assignment)
+			if result != tt.expected {
+				t.Errorf("Eval(%v) of %s = %v; want %v", tt.assignment, c.String(), result, tt.expected)
+			}
+		})
+	}
+	if New().Eval(map[Literal]bool{1: true}) {
+		t.Errorf("Eval() of empty clause = true; want false")
+	}
+}
+
+func TestAssignments(t *testing.T) {
+	rows := []string{}
+	Assignments([]Literal{1, 2}, func(assignment map[Literal]bool) bool {
+		row := ""
+		for _, v := range []Literal{1, 2} {
+			if assignment[v] {
+				row += "1"
+			} else {
+				row += "0"
+			}
+		}
+		rows = append(rows, row)
+		return true
+	})
+	expected := []string{"00", "01", "10", "11"}
+	if len(rows) != len(expected) {
+		t.Fatalf("Assignments() = %v; want %v", rows, expected)
+	}
+	for i := range rows {
+		if rows[i] != expected[i] {
+			t.Errorf("Assignments() = %v; want %v", rows, expected)
+			break
+		}
+	}
+	calls := 0
+	Assignments([]Literal{}, func(map[Literal]bool) bool {
+		calls++
+		return true
+	})
+	if calls != 1 {
+		t.Errorf("Assignments() of no variables called fn %d times; want 1", calls)
+	}
+	calls = 0
+	Assignments([]Literal{1, 2, 3}, func(map[Literal]bool) bool {
+		calls++
+		return calls < 2
+	})
+	if calls != 2 {
+		t.Errorf("Assignments() did not stop early: called fn %d times; want 2", calls)
+	}
+}
+
+func TestClausePivot(t *testing.T) {
+	tests := []struct {
+		name     string
+		clause1  string
+		clause2  string
+		expected Literal
+		found    bool
+	}{
+		{"single pair", "A,B", "-A,C", 1, true},
+		{"negative pivot", "-B,C", "A,B", -2, true},
+		{"no complementary literals", "A,B", "B,C", ErrorLiteral, false},
+		{"two complementary pairs", "A,B", "-A,-B", ErrorLiteral, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c1, _ := Parse(tt.clause1)
+			c2, _ := Parse(tt.clause2)
+			pivot, found := c1.Pivot(*c2)
+			if pivot != tt.expected || found != tt.found {
+				t.Errorf("Pivot() = %s, %v; want %s, %v", Lit2Str(pivot), found, Lit2Str(tt.expected), tt.found)
+			}
+			if _, resolved := c1.Resolve(*c2); resolved != found {
+				t.Errorf("Pivot() found = %v but Resolve() = %v", found, resolved)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	set := parseSet(t, "A,B", "-A,B", "-B")
+	steps := Round(set, 0)
+	expected := []string{"0 1 A {B}", "0 2 B {A}", "1 2 B {-A}"}
+	if len(steps) != len(expected) {
+		t.Fatalf("Round() returned %d steps; want %d", len(steps), len(expected))
+	}
+	for i, step := range steps {
+		got := fmt.Sprintf("%d %d %s %s", step.Left, step.Right, Lit2Str(step.Pivot), step.Resolvent.String())
+		if got != expected[i] {
+			t.Errorf("step %d = %q; want %q", i, got, expected[i])
+		}
+	}
+	// Only pairs involving a clause from index 2 on
+	if steps := Round(set, 2); len(steps) != 2 || steps[0].Right != 2 || steps[1].Right != 2 {
+		t.Errorf("Round(set, 2) = %v; want the two steps with right clause 2", steps)
+	}
+}
+
+func TestRoundSaturation(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for n := 0; n < 100; n++ {
+		set := randomSet(r, 1+r.Intn(4), 1+r.Intn(6), 3)
+		expected, _ := BruteForce(set)
+		saturated := append([]Clause{}, set...)
+		for index := 0; index < len(saturated); {
+			size := len(saturated)
+			for _, step := range Round(saturated, index) {
+				if Index(saturated, step.Resolvent) < 0 {
+					saturated = append(saturated, step.Resolvent)
+				}
+			}
+			index = size
+		}
+		if found := Index(saturated, *New()) >= 0; found != expected {
+			t.Errorf("saturation found empty clause = %v; BruteForce() = %v for clauses %s", found, expected, formatClauses(set))
+		}
+	}
+}
+
+func TestRefute(t *testing.T) {
+	set := parseSet(t, "A,B", "-A,B", "A,-B", "-A,-B", "C")
+	unsat, steps := Refute(set, nil)
+	if !unsat {
+		t.Fatalf("Refute() = false; want true")
+	}
+	certify(t, set, steps)
+	// The unit clause {C} plays no part in the refutation
+	for _, step := range steps {
+		if step.Left == 4 || step.Right == 4 {
+			t.Errorf("refutation uses the unrelated clause {C}: %+v", step)
+		}
+	}
+	if unsat, steps := Refute(parseSet(t, "A,B", "-A"), nil); unsat || steps != nil {
+		t.Errorf("Refute() = %v, %v; want false, nil", unsat, steps)
+	}
+	if unsat, steps := Refute([]Clause{*New()}, nil); !unsat || len(steps) != 0 {
+		t.Errorf("Refute({}) = %v, %v; want true without steps", unsat, steps)
+	}
+}
+
+func TestRefuteAgreesWithRes(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for n := 0; n < 200; n++ {
+		set := randomSet(r, 1+r.Intn(4), 1+r.Intn(7), 3)
+		copied := append([]Clause{}, set...)
+		expected := Res(copied, 0)
+		unsat, steps := Refute(set, nil)
+		if unsat != expected {
+			t.Fatalf("Refute() = %v; Res() = %v for clauses %s", unsat, expected, formatClauses(set))
+		}
+		if expected {
+			certify(t, set, steps)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	set := parseSet(t, "A,B", "-A", "B,A")
+	c, _ := Parse("B,A")
+	if i := Index(set, *c); i != 0 {
+		t.Errorf("Index(%s) = %d; want 0", c.String(), i)
+	}
+	if i := Index(set, *New()); i != -1 {
+		t.Errorf("Index({}) = %d; want -1", i)
+	}
+}
+
+func TestBruteForce(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		expected bool
+	}{
+		{"empty clause set", []string{}, false},
+		{"simple contradiction", []string{"A", "-A"}, true},
+		{"requires resolution", []string{"A,B", "-A,B", "A,-B", "-A,-B"}, true},
+		{"satisfiable", []string{"A,B", "-A,C", "B,-C"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := []Clause{}
+			for _, s := range tt.clauses {
+				c, err := Parse(s)
+				if err != nil {
+					t.Fatalf("Parse(%q) failed: %v", s, err)
+				}
+				set = append(set, *c)
+			}
+			result, model := BruteForce(set)
+			if result != tt.expected {
+				t.Fatalf("BruteForce(%v) = %v; want %v", tt.clauses, result, tt.expected)
+			}
+			if result {
+				return
+			}
+			assignment := map[Literal]bool{}
+			for _, l := range model {
+				assignment[l.Var()] = l > 0
+			}
+			for i := range set {
+				if !set[i].Eval(assignment) {
+					t.Errorf("BruteForce(%v) model = %v falsifies %s", tt.clauses, model, set[i].String())
+				}
+			}
+		})
+	}
+	if result, _ := BruteForce([]Clause{*New()}); !result {
+		t.Errorf("BruteForce() = false; want true for set containing the empty clause")
+	}
+}
+
+func TestResStats(t *testing.T) {
+	set := parseSet(t, "A,B", "-A,B", "A,-B", "-A,-B")
+	st := &stats.Stats{}
+	if !ResStats(set, st) {
+		t.Fatalf("ResStats() = false; want true")
+	}
+	if st.Rounds < 1 || st.Resolvents < 1 {
+		t.Errorf("ResStats() recorded %d rounds and %d resolvents; want at least 1 each", st.Rounds, st.Resolvents)
+	}
+	if st.PeakClauses != len(set)+st.Resolvents {
+		t.Errorf("PeakClauses = %d; want %d input clauses plus %d resolvents", st.PeakClauses, len(set), st.Resolvents)
+	}
+	if st.MaxClauseSize != 2 {
+		t.Errorf("MaxClauseSize = %d; want 2", st.MaxClauseSize)
+	}
+	// {A, B} and {-A, -B} clash on two literals
+	if st.Tautologies == 0 {
+		t.Errorf("Tautologies = 0; want discarded tautological resolvents")
+	}
+	if st.Decisions != 0 || st.Conflicts != 0 || st.Propagations != 0 {
+		t.Errorf("ResStats() recorded search counters: %+v", st)
+	}
+}
+
+func TestResStatsSteps(t *testing.T) {
+	set := parseSet(t, "A,B", "-A,B", "A,-B", "-A,-B")
+	// ResStats is resParallelSteps on one worker, which records the derived clauses if asked to
+	derived := []Step{}
+	if !resParallelSteps(set, 1, &stats.Stats{}, &derived) {
+		t.Fatalf("resParallelSteps() = false; want true")
+	}
+	certify(t, set, derived)
+}
+
+func TestResAgreesWithBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		set := randomSet(r, 1+r.Intn(5), 1+r.Intn(8), 3)
+		expected, _ := BruteForce(set)
+		copied := make([]Clause, len(set))
+		copy(copied, set)
+		derived := []Step{}
+		result := resSteps(copied, 0, &derived)
+		if result != expected {
+			t.Errorf("Res() = %v; BruteForce() = %v for clauses %s", result, expected, formatClauses(set))
+		}
+		if result {
+			certify(t, set, derived)
+		}
+	}
+}
+
+func TestResRandom3CNF(t *testing.T) {
+	// Random 3-CNF with about 4.26 clauses per variable lies near the phase transition,
+	// where roughly half of the instances are unsatisfiable
+	r := rand.New(rand.NewSource(42))
+	rounds := 100
+	if testing.Short() {
+		rounds = 20
+	}
+	for i := 0; i < rounds; i++ {
+		vars := 3 + r.Intn(3)
+		set := random3CNF(r, vars, (vars*426+50)/100)
+		expected, _ := BruteForce(set)
+		copied := make([]Clause, len(set))
+		copy(copied, set)
+		derived := []Step{}
+		result := resSteps(copied, 0, &derived)
+		if result != expected {
+			t.Errorf("Res() = %v; BruteForce() = %v for clauses %s", result, expected, formatClauses(set))
+		}
+		if result {
+			certify(t, set, derived)
+		}
+	}
+}
+
+func TestResParallelMatchesSequential(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 20; i++ {
+		vars := 3 + r.Intn(3)
+		set := random3CNF(r, vars, (vars*426+50)/100)
+		expected := stats.Stats{}
+		unsat := ResStats(append([]Clause{}, set...), &expected)
+		_, steps := RefuteParallel(set, 1, nil)
+		for _, workers := range []int{2, 3, 8} {
+			st := stats.Stats{}
+			if got := ResParallel(append([]Clause{}, set...), workers, &st); got != unsat {
+				t.Errorf("ResParallel(%s, %d) = %v; want %v", formatClauses(set), workers, got, unsat)
+			}
+			if st != expected {
+				t.Errorf("ResParallel(%s, %d) stats = %+v; want %+v", formatClauses(set), workers, st, expected)
+			}
+			got, parallel := RefuteParallel(set, workers, nil)
+			if got != unsat || fmt.Sprint(parallel) != fmt.Sprint(steps) {
+				t.Errorf("RefuteParallel(%s, %d) = %v, %v; want %v, %v", formatClauses(set), workers, got, parallel, unsat, steps)
+			}
+		}
+	}
+}
+
+func TestRefuteContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	set := parseSet(t, "A,B", "-A,B", "A,-B", "-A,-B")
+	if unsat, steps, err := RefuteContext(ctx, set, 1, nil); unsat || steps != nil || err != context.Canceled {
+		t.Errorf("RefuteContext() with a cancelled context = %v, %v, %v; want false, nil, %v", unsat, steps, err, context.Canceled)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{"A", "-a", "A,B,-C", " a , -b ", "A,-A", "A,,B", "--A", "A-", "ABC", "Ä", "-ß", "\xff", "A,\u00e9", "{A, B}"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		c, err := Parse(s)
+		if err != nil {
+			if c != nil {
+				t.Errorf("Parse(%q) returned a clause and an error", s)
+			}
+			return
+		}
+		if c.Size() > len(lit2Str) {
+			t.Errorf("Parse(%q) has %d literals; want at most %d", s, c.Size(), len(lit2Str))
+		}
+		for _, l := range c.Literals() {
+			if Lit2Str(l) == "?" {
+				t.Errorf("Parse(%q) contains invalid literal %d", s, l)
+			}
+		}
+		if c.IsEmpty() {
+			return
+		}
+		// String uses set notation, Parse expects the bare literal list
+		again, err := Parse(strings.Trim(c.String(), "{}"))
+		if err != nil {
+			t.Fatalf("Parse(%q) failed on the output of String for input %q: %v", c.String(), s, err)
+		}
+		if !again.Equals(*c) {
+			t.Errorf("Parse(%q) = %s; want %s", c.String(), again.String(), c.String())
+		}
+	})
+}
+
+func FuzzStr2Lit(f *testing.F) {
+	for _, s := range []string{"A", "z", "-A", "-z", "", "-", "--", "AB", "1", "@", "Ä", "-é", "\xff", "\x00"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		l := Str2Lit(s)
+		if l == ErrorLiteral {
+			return
+		}
+		if Lit2Str(l) != strings.ToUpper(s) {
+			t.Errorf("Lit2Str(Str2Lit(%q)) = %q; want %q", s, Lit2Str(l), strings.ToUpper(s))
+		}
+		if again := Str2Lit(Lit2Str(l)); again != l {
+			t.Errorf("Str2Lit(Lit2Str(%d)) = %d; want %d", l, again, l)
+		}
+	})
+}
+
 func TestRes(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1829,15 +2320,99 @@ func TestRes(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := Res(tt.clauses, 0)
+			derived := []Step{}
+			result := resSteps(tt.clauses, 0, &derived)
 			if result != tt.expected {
 				t.Errorf("Res() = %v; want %v for clauses %s",
 					result, tt.expected, formatClauses(tt.clauses))
 			}
+			if result {
+				certify(t, tt.clauses, derived)
+			}
 		})
 	}
 }
 
+func BenchmarkParse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Parse("A,-B,C,-D,E,-F,G,-H")
+	}
+}
+
+func BenchmarkResolve(b *testing.B) {
+	c1, _ := Parse("A,-B,C,-D,E")
+	c2, _ := Parse("-A,-B,F,G,H")
+	for i := 0; i < b.N; i++ {
+		c1.Resolve(*c2)
+	}
+}
+
+func BenchmarkRes(b *testing.B) {
+	for _, vars := range []int{3, 4, 5} {
+		set := random3CNF(rand.New(rand.NewSource(int64(vars))), vars, (vars*426+50)/100)
+		b.Run(fmt.Sprintf("vars=%d", vars), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				copied := make([]Clause, len(set))
+				copy(copied, set)
+				Res(copied, 0)
+			}
+		})
+	}
+}
+
+func BenchmarkBruteForce(b *testing.B) {
+	set := random3CNF(rand.New(rand.NewSource(1)), 10, 43)
+	for i := 0; i < b.N; i++ {
+		BruteForce(set)
+	}
+}
+
+// Helper function to parse a clause set from strings
+func parseSet(t *testing.T, clauses ...string) []Clause {
+	t.Helper()
+	set := []Clause{}
+	for _, s := range clauses {
+		c, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", s, err)
+		}
+		set = append(set, *c)
+	}
+	return set
+}
+
+// Helper function to certify that a run found a contradiction: the steps it recorded that lead to
+// the empty clause are replayed by the independent checker package. If the run did not derive
+// the empty clause, it found it in the set, which the checker accepts without steps.
+func certify(t *testing.T, set []Clause, steps []Step) {
+	t.Helper()
+	if len(steps) > 0 && steps[len(steps)-1].Resolvent.IsEmpty() {
+		steps = trim(len(set), steps)
+	} else {
+		steps = nil
+	}
+	inputs := make([][]int, len(set))
+	for i := range set {
+		inputs[i] = toInts(set[i])
+	}
+	replay := make([]checker.Step, len(steps))
+	for i, step := range steps {
+		replay[i] = checker.Step{Left: step.Left, Right: step.Right, Pivot: int(step.Pivot), Resolvent: toInts(step.Resolvent)}
+	}
+	if err := checker.Check(inputs, replay); err != nil {
+		t.Errorf("refutation of %s rejected: %v", formatClauses(set), err)
+	}
+}
+
+// Helper function to convert a clause to the literal numbering of the checker
+func toInts(c Clause) []int {
+	result := []int{}
+	for _, l := range c.Literals() {
+		result = append(result, int(l))
+	}
+	return result
+}
+
 // Helper function to format clauses for better error messages
 func formatClauses(clauses []Clause) string {
 	result := "["
@@ -1849,3 +2424,37 @@ func formatClauses(clauses []Clause) string {
 	}
 	return result + "]"
 }
+
+// Helper function to generate a random clause set with up to width literals per clause
+func randomSet(r *rand.Rand, vars, clauses, width int) []Clause {
+	set := make([]Clause, clauses)
+	for i := range set {
+		c := New()
+		for k := 1 + r.Intn(width); k > 0; k-- {
+			l := Literal(1 + r.Intn(vars))
+			if r.Intn(2) == 0 {
+				l = -l
+			}
+			c.Insert(l)
+		}
+		set[i] = *c
+	}
+	return set
+}
+
+// Helper function to generate a random 3-CNF with three distinct variables per clause
+func random3CNF(r *rand.Rand, vars, clauses int) []Clause {
+	set := make([]Clause, clauses)
+	for i := range set {
+		c := New()
+		for _, v := range r.Perm(vars)[:3] {
+			l := Literal(v + 1)
+			if r.Intn(2) == 0 {
+				l = -l
+			}
+			c.Insert(l)
+		}
+		set[i] = *c
+	}
+	return set
+}
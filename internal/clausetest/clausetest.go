@@ -0,0 +1,64 @@
+// Package clausetest provides helpers for the tests of the packages working on clause sets.
+package clausetest
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+// ParseSet parses every argument as a clause in the format A,B,-C and fails the test on an
+// invalid clause. The empty string is the empty clause.
+func ParseSet(t testing.TB, clauses ...string) []clause.Clause {
+	t.Helper()
+	set := []clause.Clause{}
+	for _, s := range clauses {
+		if s == "" {
+			set = append(set, *clause.New())
+			continue
+		}
+		c, err := clause.Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", s, err)
+		}
+		set = append(set, *c)
+	}
+	return set
+}
+
+// Satisfies checks if every clause of the set contains a literal of the model,
+// given as the list of its true literals.
+func Satisfies(set []clause.Clause, model []clause.Literal) bool {
+	for i := range set {
+		satisfied := false
+		for _, l := range model {
+			if set[i].Contains(l) {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			return false
+		}
+	}
+	return true
+}
+
+// RandomSet returns the given number of clauses of one to three random literals over the
+// variables 1 to vars. Clauses with a complementary pair lose both literals, so some may be empty.
+func RandomSet(r *rand.Rand, vars, clauses int) []clause.Clause {
+	set := make([]clause.Clause, clauses)
+	for i := range set {
+		c := clause.New()
+		for k := 1 + r.Intn(3); k > 0; k-- {
+			l := clause.Literal(1 + r.Intn(vars))
+			if r.Intn(2) == 0 {
+				l = -l
+			}
+			c.Insert(l)
+		}
+		set[i] = *c
+	}
+	return set
+}
@@ -0,0 +1,234 @@
+// Package count provides exact model counting (#SAT) for sets of clauses.
+package count
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/dpll"
+)
+
+// Count returns the number of satisfying assignments of the clause set.
+//
+// If project is empty, assignments of all variables of the set are counted.
+// Otherwise only the given variables are counted: the result is the number of
+// assignments of these variables that can be extended to a model (projected counting).
+// Projected variables not occurring in the set are free and double the count.
+//
+// The algorithm works by:
+// 1. Splitting the clause set into components that share no variables and multiplying their counts
+// 2. Branching on the most frequent variable of a component and adding the counts of both branches
+// 3. Caching the count of every component, so components reached again are not counted twice
+func Count(set []clause.Clause, project []clause.Literal) *big.Int {
+	c := &counter{cache: map[string]*big.Int{}}
+	vars := clause.Variables(set)
+	if len(project) > 0 {
+		c.project = map[clause.Literal]bool{}
+		for _, v := range project {
+			c.project[v.Var()] = true
+		}
+		// Free projected variables still double the count
+		for v := range c.project {
+			if !contains(vars, v) {
+				vars = append(vars, v)
+			}
+		}
+	}
+	clauses := make([][]clause.Literal, len(set))
+	for i := range set {
+		clauses[i] = set[i].Literals()
+	}
+	return c.count(clauses, vars)
+}
+
+// counter holds the state of a single model count.
+type counter struct {
+	// project holds the counted variables, nil means every variable is counted
+	project map[clause.Literal]bool
+	// cache maps the key of a component to its count
+	cache map[string]*big.Int
+}
+
+// counted checks if assignments of the variable are counted.
+func (c *counter) counted(v clause.Literal) bool {
+	return c.project == nil || c.project[v]
+}
+
+// count returns the number of models of the clauses over the given variables,
+// which must include every variable of the clauses.
+func (c *counter) count(clauses [][]clause.Literal, vars []clause.Literal) *big.Int {
+	occurring := map[clause.Literal]bool{}
+	for _, cl := range clauses {
+		if len(cl) == 0 {
+			return big.NewInt(0)
+		}
+		for _, l := range cl {
+			occurring[l.Var()] = true
+		}
+	}
+	result := big.NewInt(1)
+	for _, v := range vars {
+		if !occurring[v] && c.counted(v) {
+			result.Lsh(result, 1)
+		}
+	}
+	for _, comp := range components(clauses) {
+		n := c.component(comp)
+		if n.Sign() == 0 {
+			return n
+		}
+		result.Mul(result, n)
+	}
+	return result
+}
+
+// component returns the number of models of a connected set of clauses over its own variables.
+func (c *counter) component(clauses [][]clause.Literal) *big.Int {
+	k := key(clauses)
+	if n, ok := c.cache[k]; ok {
+		return new(big.Int).Set(n)
+	}
+	v, counts := pick(clauses, c.counted)
+	var n *big.Int
+	if !counts {
+		// No counted variable is left, only satisfiability matters
+		n = big.NewInt(1)
+		if unsat, _ := dpll.Solve(toClauses(clauses), nil, nil); unsat {
+			n = big.NewInt(0)
+		}
+	} else {
+		vars := []clause.Literal{}
+		for _, u := range variables(clauses) {
+			if u != v {
+				vars = append(vars, u)
+			}
+		}
+		n = c.count(condition(clauses, v), vars)
+		n.Add(n, c.count(condition(clauses, -v), vars))
+	}
+	c.cache[k] = new(big.Int).Set(n)
+	return n
+}
+
+// pick returns the most frequent counted variable of the clauses, ties broken by the smallest variable.
+// If no counted variable occurs, it returns the most frequent variable and false.
+func pick(clauses [][]clause.Literal, counted func(clause.Literal) bool) (clause.Literal, bool) {
+	occurrences := map[clause.Literal]int{}
+	for _, cl := range clauses {
+		for _, l := range cl {
+			occurrences[l.Var()]++
+		}
+	}
+	best := clause.ErrorLiteral
+	bestCounted := false
+	for v, n := range occurrences {
+		better := best == clause.ErrorLiteral ||
+			(counted(v) && !bestCounted) ||
+			(counted(v) == bestCounted && (n > occurrences[best] || (n == occurrences[best] && v < best)))
+		if better {
+			best = v
+			bestCounted = counted(v)
+		}
+	}
+	return best, bestCounted
+}
+
+// condition returns the clauses after making the literal l true:
+// clauses containing l are removed and -l is removed from the others.
+func condition(clauses [][]clause.Literal, l clause.Literal) [][]clause.Literal {
+	result := [][]clause.Literal{}
+	for _, cl := range clauses {
+		satisfied := false
+		reduced := make([]clause.Literal, 0, len(cl))
+		for _, x := range cl {
+			if x == l {
+				satisfied = true
+				break
+			}
+			if x != -l {
+				reduced = append(reduced, x)
+			}
+		}
+		if !satisfied {
+			result = append(result, reduced)
+		}
+	}
+	return result
+}
+
+// components splits the clauses into groups that share no variables.
+func components(clauses [][]clause.Literal) [][][]clause.Literal {
+	parent := map[clause.Literal]clause.Literal{}
+	var find func(v clause.Literal) clause.Literal
+	find = func(v clause.Literal) clause.Literal {
+		if p, ok := parent[v]; ok && p != v {
+			parent[v] = find(p)
+			return parent[v]
+		}
+		parent[v] = v
+		return v
+	}
+	for _, cl := range clauses {
+		for _, l := range cl[1:] {
+			parent[find(l.Var())] = find(cl[0].Var())
+		}
+	}
+	index := map[clause.Literal]int{}
+	result := [][][]clause.Literal{}
+	for _, cl := range clauses {
+		root := find(cl[0].Var())
+		i, ok := index[root]
+		if !ok {
+			i = len(result)
+			index[root] = i
+			result = append(result, nil)
+		}
+		result[i] = append(result[i], cl)
+	}
+	return result
+}
+
+// key returns a canonical representation of a set of clauses, independent of clause order.
+func key(clauses [][]clause.Literal) string {
+	parts := make([]string, len(clauses))
+	for i, cl := range clauses {
+		s := make([]string, len(cl))
+		for k, l := range cl {
+			s[k] = strconv.Itoa(int(l))
+		}
+		parts[i] = strings.Join(s, ",")
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+// variables returns the variables of the clauses in ascending order.
+func variables(clauses [][]clause.Literal) []clause.Literal {
+	return clause.Variables(toClauses(clauses))
+}
+
+// toClauses converts literal slices back into clauses.
+func toClauses(clauses [][]clause.Literal) []clause.Clause {
+	result := make([]clause.Clause, len(clauses))
+	for i, cl := range clauses {
+		c := clause.New()
+		for _, l := range cl {
+			c.Insert(l)
+		}
+		result[i] = *c
+	}
+	return result
+}
+
+// contains checks if the literal is in the slice.
+func contains(literals []clause.Literal, l clause.Literal) bool {
+	for _, x := range literals {
+		if x == l {
+			return true
+		}
+	}
+	return false
+}
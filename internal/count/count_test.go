@@ -0,0 +1,99 @@
+package count
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+	"github.com/thxrsxm/res/internal/models"
+)
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		project  []clause.Literal
+		expected int64
+	}{
+		{"empty clause set", []string{}, nil, 1},
+		{"unsatisfiable", []string{"A", "-A"}, nil, 0},
+		{"subsumed clause", []string{"A,B", "A"}, nil, 2},
+		{"single unit", []string{"-A"}, nil, 1},
+		{"disjunction", []string{"A,B"}, nil, 3},
+		{"three literals", []string{"A,B,C"}, nil, 7},
+		{"independent components", []string{"A,B", "C,D"}, nil, 9},
+		{"chain", []string{"-A,B", "-B,C", "-C,D"}, nil, 5},
+		{"requires resolution", []string{"A,B", "-A,B", "A,-B", "-A,-B"}, nil, 0},
+		{"projection", []string{"A,B", "-A,C"}, []clause.Literal{1}, 2},
+		{"projection onto two variables", []string{"A,B", "-A,C"}, []clause.Literal{2, 3}, 3},
+		{"projection onto free variable", []string{"A"}, []clause.Literal{1, 2}, 2},
+		{"projection unsatisfiable", []string{"A", "-A,B", "-B"}, []clause.Literal{1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Count(clausetest.ParseSet(t, tt.clauses...), tt.project)
+			if result.Cmp(big.NewInt(tt.expected)) != 0 {
+				t.Errorf("Count(%v, %v) = %s; want %d", tt.clauses, tt.project, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCountLarge(t *testing.T) {
+	all := clause.New()
+	vars := []clause.Literal{}
+	for v := clause.Literal(1); v <= 26; v++ {
+		all.Insert(v)
+		vars = append(vars, v)
+	}
+	// Every assignment except the all-false one satisfies A ∨ B ∨ ... ∨ Z
+	expected := new(big.Int).Lsh(big.NewInt(1), 26)
+	expected.Sub(expected, big.NewInt(1))
+	if result := Count([]clause.Clause{*all}, nil); result.Cmp(expected) != 0 {
+		t.Errorf("Count(A ∨ ... ∨ Z) = %s; want %s", result, expected)
+	}
+	// Projecting the empty set onto every variable counts every assignment
+	expected.Add(expected, big.NewInt(1))
+	if result := Count([]clause.Clause{}, vars); result.Cmp(expected) != 0 {
+		t.Errorf("Count({}, A..Z) = %s; want %s", result, expected)
+	}
+}
+
+func TestCountMatchesEnumeration(t *testing.T) {
+	sets := [][]string{
+		{"A,B,C", "-A,-B", "-B,-C", "A,-C,D"},
+		{"A,-B", "B,-C", "C,-D", "D,-A", "A,C"},
+		{"A,B", "C,D", "-A,-C", "E,-B,-D"},
+		{"-A,B,C", "A,-B,C", "A,B,-C", "-A,-B,-C"},
+	}
+	for _, clauses := range sets {
+		set := clausetest.ParseSet(t, clauses...)
+		for _, project := range [][]clause.Literal{nil, {1, 2}, {3}} {
+			expected := models.Enumerate(set, project, 0, func([]clause.Literal) bool { return true })
+			if result := Count(set, project); result.Cmp(big.NewInt(int64(expected))) != 0 {
+				t.Errorf("Count(%v, %v) = %s; enumeration found %d", clauses, project, result, expected)
+			}
+		}
+	}
+}
+
+func TestComponents(t *testing.T) {
+	clauses := [][]clause.Literal{{1, 2}, {3}, {-2, 4}, {-3, 5}, {6}}
+	result := components(clauses)
+	if len(result) != 3 {
+		t.Fatalf("components(%v) = %v; want 3 components", clauses, result)
+	}
+	sizes := []int{len(result[0]), len(result[1]), len(result[2])}
+	if sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Errorf("component sizes = %v; want [2 2 1]", sizes)
+	}
+}
+
+func TestKey(t *testing.T) {
+	a := key([][]clause.Literal{{1, -2}, {3}})
+	b := key([][]clause.Literal{{3}, {1, -2}})
+	if a != b {
+		t.Errorf("key() depends on clause order: %q != %q", a, b)
+	}
+}
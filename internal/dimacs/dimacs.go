@@ -0,0 +1,79 @@
+// Package dimacs reads and writes clause sets in the DIMACS CNF format used by most SAT solvers.
+package dimacs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+// Write writes the clause set in DIMACS CNF format.
+// Variables keep their numbering (A is 1, B is 2, ...) and every clause is terminated by 0.
+//
+// Example: the clauses {A, -B} and {B} are written as
+//
+//	p cnf 2 2
+//	1 -2 0
+//	2 0
+func Write(w io.Writer, set []clause.Clause) error {
+	bw := bufio.NewWriter(w)
+	vars := clause.Variables(set)
+	maxVar := 0
+	if len(vars) > 0 {
+		maxVar = int(vars[len(vars)-1])
+	}
+	fmt.Fprintf(bw, "p cnf %d %d\n", maxVar, len(set))
+	for i := range set {
+		for _, l := range set[i].Literals() {
+			fmt.Fprintf(bw, "%d ", l)
+		}
+		fmt.Fprintln(bw, "0")
+	}
+	return bw.Flush()
+}
+
+// Read reads a clause set in DIMACS CNF format.
+// Comment lines starting with c are skipped, and the problem line (p cnf <variables> <clauses>) is optional.
+// Clauses are terminated by 0 and may span several lines.
+// Returns an error for literals that are not numbers or exceed clause.MaxVariable.
+func Read(r io.Reader) ([]clause.Clause, error) {
+	set := []clause.Clause{}
+	current := clause.New()
+	open := false
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "c") || strings.HasPrefix(text, "p") {
+			continue
+		}
+		for _, field := range strings.Fields(text) {
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid literal %q", line, field)
+			}
+			if n == 0 {
+				set = append(set, *current)
+				current = clause.New()
+				open = false
+				continue
+			}
+			l := clause.Literal(n)
+			if l.Var() > clause.MaxVariable {
+				return nil, fmt.Errorf("line %d: variable %d exceeds the maximum of %d", line, l.Var(), clause.MaxVariable)
+			}
+			current.Insert(l)
+			open = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, fmt.Errorf("last clause is not terminated by 0")
+	}
+	return set, nil
+}
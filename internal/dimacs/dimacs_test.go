@@ -0,0 +1,104 @@
+package dimacs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		expected string
+	}{
+		{"empty clause set", []string{}, "p cnf 0 0\n"},
+		{"single clause", []string{"A,-B"}, "p cnf 2 1\n1 -2 0\n"},
+		{"multiple clauses", []string{"A,-C", "-B", "Z"}, "p cnf 26 3\n1 -3 0\n-2 0\n26 0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := Write(&sb, clausetest.ParseSet(t, tt.clauses...)); err != nil {
+				t.Fatalf("Write() failed: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("Write(%v) = %q; want %q", tt.clauses, sb.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteEmptyClause(t *testing.T) {
+	var sb strings.Builder
+	if err := Write(&sb, []clause.Clause{*clause.New()}); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if expected := "p cnf 0 1\n0\n"; sb.String() != expected {
+		t.Errorf("Write() = %q; want %q", sb.String(), expected)
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []string
+		expectError bool
+	}{
+		{"empty input", "", []string{}, false},
+		{"header only", "p cnf 0 0\n", []string{}, false},
+		{"comments", "c a comment\np cnf 2 1\nc another\n1 -2 0\n", []string{"A,-B"}, false},
+		{"multiple clauses", "p cnf 3 2\n1 -3 0\n-2 0\n", []string{"A,-C", "-B"}, false},
+		{"clause spanning lines", "1 2\n3 0\n", []string{"A,B,C"}, false},
+		{"several clauses on a line", "1 0 -1 0\n", []string{"A", "-A"}, false},
+		{"invalid literal", "1 x 0\n", nil, true},
+		{"variable too large", "27 0\n", nil, true},
+		{"unterminated clause", "1 2\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Read(strings.NewReader(tt.input))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Read(%q) succeeded; want error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read(%q) failed: %v", tt.input, err)
+			}
+			expected := clausetest.ParseSet(t, tt.expected...)
+			if len(result) != len(expected) {
+				t.Fatalf("Read(%q) = %v; want %v", tt.input, result, tt.expected)
+			}
+			for i := range result {
+				if !result[i].Equals(expected[i]) {
+					t.Errorf("Read(%q)[%d] = %s; want %s", tt.input, i, result[i].String(), expected[i].String())
+				}
+			}
+		})
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	set := clausetest.ParseSet(t, "A,-B,C", "-Z", "M,N")
+	var sb strings.Builder
+	if err := Write(&sb, set); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	result, err := Read(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if len(result) != len(set) {
+		t.Fatalf("Read(Write()) = %v; want %v", result, set)
+	}
+	for i := range set {
+		if !result[i].Equals(set[i]) {
+			t.Errorf("Read(Write())[%d] = %s; want %s", i, result[i].String(), set[i].String())
+		}
+	}
+}
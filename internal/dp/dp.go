@@ -0,0 +1,143 @@
+// Package dp implements the Davis–Putnam variable elimination procedure.
+package dp
+
+import (
+	"context"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/drat"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+// Step records the elimination of a single variable.
+type Step struct {
+	// Variable is the eliminated variable (always positive).
+	Variable clause.Literal
+	// Added is the number of new resolvents added to the clause set.
+	Added int
+	// Removed is the number of clauses on the variable that were removed.
+	Removed int
+	// Size is the number of clauses left after the elimination.
+	Size int
+}
+
+// Solve checks if a set of clauses is unsatisfiable using the Davis–Putnam procedure.
+// It returns true if the clause set is unsatisfiable, false if it is satisfiable,
+// together with a trace of the eliminated variables.
+//
+// The algorithm works by:
+// 1. Picking the variable that occurs in the fewest clauses
+// 2. Replacing all clauses on that variable with their non-tautological resolvents
+// 3. Stopping when the empty clause is derived (unsatisfiable) or no variables are left (satisfiable)
+//
+// If st is not nil, the eliminated variables are recorded as rounds together with
+// resolvents, duplicates, tautologies, peak clause count and largest clause.
+// If proof is not nil, a DRAT proof is recorded: the resolvents of every elimination are added,
+// then the clauses on the eliminated variable are deleted.
+func Solve(set []clause.Clause, st *stats.Stats, proof *drat.Proof) (bool, []Step) {
+	unsat, steps, _ := SolveContext(context.Background(), set, st, proof)
+	return unsat, steps
+}
+
+// SolveContext works like Solve, but stops when the context is done and returns its error.
+// The context is checked before every elimination and every clause whose resolvents are added.
+func SolveContext(ctx context.Context, set []clause.Clause, st *stats.Stats, proof *drat.Proof) (bool, []Step, error) {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	current := make([]clause.Clause, 0, len(set))
+	for i := range set {
+		if set[i].IsEmpty() {
+			proof.Add(set[i])
+			return true, []Step{}, nil
+		}
+		if !contains(current, set[i]) {
+			current = append(current, set[i])
+		}
+	}
+	steps := []Step{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return false, nil, err
+		}
+		v := pick(current)
+		if v == clause.ErrorLiteral {
+			return false, steps, nil
+		}
+		var pos, neg []clause.Clause
+		next := []clause.Clause{}
+		for i := range current {
+			switch {
+			case current[i].Contains(v):
+				pos = append(pos, current[i])
+			case current[i].Contains(-v):
+				neg = append(neg, current[i])
+			default:
+				next = append(next, current[i])
+			}
+		}
+		step := Step{Variable: v, Removed: len(pos) + len(neg)}
+		st.Rounds++
+		for i := range pos {
+			if err := ctx.Err(); err != nil {
+				return false, nil, err
+			}
+			for k := range neg {
+				c, resolved := pos[i].Resolve(neg[k])
+				// Resolvents with more than one complementary pair are tautologies
+				if !resolved || c == nil {
+					st.Tautologies++
+					continue
+				}
+				if contains(next, *c) {
+					st.Duplicates++
+					continue
+				}
+				next = append(next, *c)
+				proof.Add(*c)
+				step.Added++
+				st.Resolvents++
+				st.ClauseSize(c.Size())
+				st.Clauses(len(next) + len(pos) + len(neg))
+				if c.IsEmpty() {
+					step.Size = len(next)
+					return true, append(steps, step), nil
+				}
+			}
+		}
+		for _, c := range append(pos, neg...) {
+			proof.Delete(c)
+		}
+		step.Size = len(next)
+		steps = append(steps, step)
+		current = next
+	}
+}
+
+// pick returns the variable occurring in the fewest clauses.
+// Ties are broken by the smallest variable. Returns ErrorLiteral if no variables are left.
+func pick(set []clause.Clause) clause.Literal {
+	counts := map[clause.Literal]int{}
+	for i := range set {
+		for _, l := range set[i].Literals() {
+			counts[l.Var()]++
+		}
+	}
+	best := clause.ErrorLiteral
+	for v, n := range counts {
+		if best == clause.ErrorLiteral || n < counts[best] || (n == counts[best] && v < best) {
+			best = v
+		}
+	}
+	return best
+}
+
+// contains checks if the clause set already contains a clause equal to c.
+func contains(set []clause.Clause, c clause.Clause) bool {
+	for i := range set {
+		if c.Equals(set[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package dp
+
+import (
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+	"github.com/thxrsxm/res/internal/drat"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		expected bool
+	}{
+		{"empty clause set", []string{}, false},
+		{"single clause", []string{"A,B"}, false},
+		{"simple contradiction", []string{"A", "-A"}, true},
+		{"requires resolution", []string{"A,B", "-A,B", "A,-B", "-A,-B"}, true},
+		{"satisfiable", []string{"A,B", "-A,C", "B,-C"}, false},
+		{"chain", []string{"A", "-A,B", "-B,C", "-C"}, true},
+		{"pure literals", []string{"A,B", "A,-B", "A,C"}, false},
+		{"tautological resolvents only", []string{"A,B", "-A,-B"}, false},
+		{"three variables unsat", []string{"A,B,C", "-A", "-B", "-C"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := clausetest.ParseSet(t, tt.clauses...)
+			proof := &drat.Proof{}
+			result, _ := Solve(set, nil, proof)
+			if result != tt.expected {
+				t.Errorf("Solve(%v) = %v; want %v", tt.clauses, result, tt.expected)
+			}
+			if expected := clause.Res(clausetest.ParseSet(t, tt.clauses...), 0); result != expected {
+				t.Errorf("Solve(%v) = %v; clause.Res = %v", tt.clauses, result, expected)
+			}
+			if result {
+				if err := drat.Check(set, proof); err != nil {
+					t.Errorf("Solve(%v) proof rejected: %v", tt.clauses, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSolveEmptyClause(t *testing.T) {
+	set := []clause.Clause{*clause.New()}
+	result, steps := Solve(set, nil, nil)
+	if !result {
+		t.Errorf("Solve() = false; want true for set containing the empty clause")
+	}
+	if len(steps) != 0 {
+		t.Errorf("len(steps) = %d; want 0", len(steps))
+	}
+}
+
+func TestSolveTrace(t *testing.T) {
+	// B occurs twice, A and C three times, so B is eliminated first
+	set := clausetest.ParseSet(t, "A,B", "-B,C", "-A,C", "A,-C")
+	_, steps := Solve(set, nil, nil)
+	if len(steps) == 0 {
+		t.Fatalf("Solve() returned an empty trace")
+	}
+	first := steps[0]
+	if first.Variable != 2 {
+		t.Errorf("first eliminated variable = %s; want B", clause.Lit2Str(first.Variable))
+	}
+	if first.Removed != 2 || first.Added != 1 || first.Size != 3 {
+		t.Errorf("first step = %+v; want Removed 2, Added 1, Size 3", first)
+	}
+	for i := 1; i < len(steps); i++ {
+		if steps[i].Size != steps[i-1].Size-steps[i].Removed+steps[i].Added {
+			t.Errorf("step %d = %+v does not follow from size %d", i, steps[i], steps[i-1].Size)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		expected clause.Literal
+	}{
+		{"no variables", []string{}, clause.ErrorLiteral},
+		{"single variable", []string{"-C"}, 3},
+		{"fewest occurrences", []string{"A,B", "A,-B", "A,C", "-A"}, 3},
+		{"tie broken by smallest", []string{"B,C", "-B,-C"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := pick(clausetest.ParseSet(t, tt.clauses...))
+			if result != tt.expected {
+				t.Errorf("pick(%v) = %d; want %d", tt.clauses, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Package dpll implements the Davis–Putnam–Logemann–Loveland search procedure.
+package dpll
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/drat"
+	"github.com/thxrsxm/res/internal/stats"
+	"github.com/thxrsxm/res/internal/trail"
+)
+
+// Solve checks if a set of clauses is unsatisfiable using the DPLL procedure.
+// It returns true if the clause set is unsatisfiable, otherwise false together with
+// a model, given as the true literals of every variable of the set sorted by variable.
+//
+// The algorithm works by:
+// 1. Assigning every literal of a unit clause (unit propagation)
+// 2. Picking an unassigned variable and trying both values
+// 3. Undoing the assignments of a value that leads to a falsified clause (backtracking)
+//
+// If st is not nil, decisions, conflicts and propagations are recorded.
+// If proof is not nil, a DRAT proof is recorded: whenever a decision fails, the clause
+// negating the current decisions is learnt and replaces the clauses learnt below it,
+// which it subsumes. An unsatisfiable search ends with the empty clause.
+func Solve(set []clause.Clause, st *stats.Stats, proof *drat.Proof) (bool, []clause.Literal) {
+	unsat, model, _ := SolveContext(context.Background(), set, Options{}, st, proof)
+	return unsat, model
+}
+
+// Options selects the order in which the search decides variables and the value it tries first.
+// The zero value decides the variables in ascending order and tries true first.
+type Options struct {
+	// Occurrences decides the variables occurring in the most clauses first.
+	Occurrences bool
+	// Seed, if not 0, shuffles the variable order before Occurrences is applied
+	// and picks the value tried first for every variable at random.
+	Seed int64
+}
+
+// SolveContext works like Solve with the given options. It stops when the context is done
+// and returns its error.
+func SolveContext(ctx context.Context, set []clause.Clause, opts Options, st *stats.Stats, proof *drat.Proof) (bool, []clause.Literal, error) {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	s := newSolver(set, st)
+	s.ctx = ctx
+	s.proof = proof
+	s.arrange(set, opts)
+	if !s.search() {
+		if s.err != nil {
+			return false, nil, s.err
+		}
+		proof.Add(*clause.New())
+		return true, nil, nil
+	}
+	return false, s.trail.Model(), nil
+}
+
+// solver holds the state of a single DPLL search.
+type solver struct {
+	// ctx stops the search when it is done, err is set to its error then
+	ctx     context.Context
+	err     error
+	clauses [][]clause.Literal
+	vars    []clause.Literal
+	// order lists the variables in the order they are decided, first maps them to the literal tried first
+	order []clause.Literal
+	first map[clause.Literal]clause.Literal
+	// trail holds the assignment, so it can be undone on backtracking
+	trail *trail.Trail
+	stats *stats.Stats
+	// decisions lists the decided literals from the top of the search down
+	decisions []clause.Literal
+	// proof records the learnt clauses, lemmas holds those not yet subsumed
+	proof  *drat.Proof
+	lemmas []clause.Clause
+}
+
+// newSolver creates a solver for the given clause set.
+func newSolver(set []clause.Clause, st *stats.Stats) *solver {
+	s := &solver{
+		clauses: make([][]clause.Literal, len(set)),
+		vars:    clause.Variables(set),
+		trail:   trail.New(),
+		stats:   st,
+	}
+	for i := range set {
+		s.clauses[i] = set[i].Literals()
+	}
+	s.order = append([]clause.Literal{}, s.vars...)
+	s.first = map[clause.Literal]clause.Literal{}
+	for _, v := range s.vars {
+		s.first[v] = v
+	}
+	return s
+}
+
+// arrange sets the decision order and the values tried first according to the options.
+func (s *solver) arrange(set []clause.Clause, opts Options) {
+	if opts.Seed != 0 {
+		r := rand.New(rand.NewSource(opts.Seed))
+		r.Shuffle(len(s.order), func(i, k int) { s.order[i], s.order[k] = s.order[k], s.order[i] })
+		for _, v := range s.vars {
+			if r.Intn(2) == 0 {
+				s.first[v] = -v
+			}
+		}
+	}
+	if opts.Occurrences {
+		count := map[clause.Literal]int{}
+		for i := range set {
+			for _, l := range set[i].Literals() {
+				count[l.Var()]++
+			}
+		}
+		sort.SliceStable(s.order, func(i, k int) bool { return count[s.order[i]] > count[s.order[k]] })
+	}
+}
+
+// search runs the DPLL procedure from the current assignment.
+// Returns true if the assignment can be extended to a model. If the context is done,
+// it sets s.err and returns false.
+func (s *solver) search() bool {
+	if s.err = s.ctx.Err(); s.err != nil {
+		return false
+	}
+	if s.trail.Propagate(s.clauses, s.stats) >= 0 {
+		return false
+	}
+	v := s.trail.Pick(s.order)
+	if v == clause.ErrorLiteral {
+		return true
+	}
+	mark := s.trail.Len()
+	first := s.first[v]
+	for _, l := range []clause.Literal{first, -first} {
+		s.stats.Decisions++
+		s.decisions = append(s.decisions, l)
+		lemmas := len(s.lemmas)
+		s.trail.Assign(l)
+		if s.search() {
+			return true
+		}
+		if s.err != nil {
+			return false
+		}
+		s.trail.Undo(mark)
+		s.learn(lemmas)
+		s.decisions = s.decisions[:len(s.decisions)-1]
+	}
+	return false
+}
+
+// learn records the clause negating the current decisions in the proof,
+// replacing the lemmas learnt since the given number of lemmas.
+func (s *solver) learn(lemmas int) {
+	if s.proof == nil {
+		return
+	}
+	c := clause.New()
+	for _, d := range s.decisions {
+		c.Insert(-d)
+	}
+	s.proof.Add(*c)
+	for _, lemma := range s.lemmas[lemmas:] {
+		s.proof.Delete(lemma)
+	}
+	s.lemmas = append(s.lemmas[:lemmas], *c)
+}
@@ -0,0 +1,115 @@
+package dpll
+
+import (
+	"context"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+	"github.com/thxrsxm/res/internal/drat"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		expected bool
+	}{
+		{"empty clause set", []string{}, false},
+		{"single clause", []string{"A,B"}, false},
+		{"simple contradiction", []string{"A", "-A"}, true},
+		{"requires resolution", []string{"A,B", "-A,B", "A,-B", "-A,-B"}, true},
+		{"satisfiable", []string{"A,B", "-A,C", "B,-C"}, false},
+		{"unit propagation", []string{"A", "-A,B", "-B,C", "-C"}, true},
+		{"requires backtracking", []string{"-A,B", "-A,-B", "A,C", "A,-C,D", "-D,-C"}, true},
+		{"three literals", []string{"A,B,C", "-A,-B,-C", "A,-B", "B,-C"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := clausetest.ParseSet(t, tt.clauses...)
+			proof := &drat.Proof{}
+			result, model := Solve(set, nil, proof)
+			if result != tt.expected {
+				t.Fatalf("Solve(%v) = %v; want %v", tt.clauses, result, tt.expected)
+			}
+			if expected := clause.Res(clausetest.ParseSet(t, tt.clauses...), 0); result != expected {
+				t.Errorf("Solve(%v) = %v; clause.Res = %v", tt.clauses, result, expected)
+			}
+			if result {
+				if err := drat.Check(set, proof); err != nil {
+					t.Errorf("Solve(%v) proof rejected: %v", tt.clauses, err)
+				}
+				return
+			}
+			if len(model) != len(clause.Variables(set)) {
+				t.Errorf("Solve(%v) model = %v does not assign every variable", tt.clauses, model)
+			}
+			if !clausetest.Satisfies(set, model) {
+				t.Errorf("Solve(%v) model = %v does not satisfy the clause set", tt.clauses, model)
+			}
+		})
+	}
+}
+
+func TestSolveEmptyClause(t *testing.T) {
+	result, model := Solve([]clause.Clause{*clause.New(), *clause.New()}, nil, nil)
+	if !result || model != nil {
+		t.Errorf("Solve() = %v, %v; want true, nil", result, model)
+	}
+}
+
+func TestSolveStats(t *testing.T) {
+	st := &stats.Stats{}
+	// A = true conflicts via B, A = false propagates C and leaves B to be decided
+	Solve(clausetest.ParseSet(t, "-A,B", "-A,-B", "A,C"), st, nil)
+	if st.Decisions != 3 || st.Conflicts != 1 || st.Propagations != 2 {
+		t.Errorf("Solve() recorded %+v; want 3 decisions, 1 conflict, 2 propagations", st)
+	}
+}
+
+func TestSolveOptions(t *testing.T) {
+	sets := [][]string{
+		{"A,B", "-A,B", "A,-B", "-A,-B"},
+		{"A,B", "-A,C", "B,-C"},
+		{"-A,B", "-A,-B", "A,C", "A,-C,D", "-D,-C"},
+		{"A,B,C", "-A,-B,-C", "A,-B", "B,-C"},
+		{"A,B,C,D", "-A,-B", "-C,-D", "-A,-C", "-B,-D", "-A,-D", "-B,-C"},
+	}
+	options := []Options{{Occurrences: true}, {Seed: 1}, {Seed: 2}, {Occurrences: true, Seed: 3}}
+	for _, clauses := range sets {
+		expected, _ := Solve(clausetest.ParseSet(t, clauses...), nil, nil)
+		for _, opts := range options {
+			set := clausetest.ParseSet(t, clauses...)
+			proof := &drat.Proof{}
+			unsat, model, err := SolveContext(context.Background(), set, opts, nil, proof)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if unsat != expected {
+				t.Errorf("SolveContext(%v, %+v) = %v; want %v", clauses, opts, unsat, expected)
+				continue
+			}
+			if unsat {
+				if err := drat.Check(set, proof); err != nil {
+					t.Errorf("SolveContext(%v, %+v) proof rejected: %v", clauses, opts, err)
+				}
+			} else if !clausetest.Satisfies(set, model) {
+				t.Errorf("SolveContext(%v, %+v) model = %v does not satisfy the clause set", clauses, opts, model)
+			}
+		}
+	}
+}
+
+func TestSolveContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	set := clausetest.ParseSet(t, "A,B", "-A,B", "A,-B", "-A,-B")
+	proof := &drat.Proof{}
+	if unsat, model, err := SolveContext(ctx, set, Options{}, nil, proof); unsat || model != nil || err != context.Canceled {
+		t.Errorf("SolveContext() with a cancelled context = %v, %v, %v; want false, nil, %v", unsat, model, err, context.Canceled)
+	}
+	if len(proof.Steps) != 0 {
+		t.Errorf("SolveContext() with a cancelled context recorded proof steps %v", proof.Steps)
+	}
+}
@@ -0,0 +1,261 @@
+// Package drat records clausal proofs of unsatisfiability and writes them in the DRAT format
+// understood by proof checkers such as drat-trim.
+//
+// A proof is a sequence of clause additions and deletions. Every added clause must follow from
+// the clauses present at that point, and the proof ends by adding the empty clause.
+// Literals use the DIMACS numbering: A is 1, B is 2, and a negative number is a negated variable.
+package drat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/trail"
+)
+
+// Step adds or deletes a single clause.
+type Step struct {
+	// Delete is true if the clause is deleted, false if it is added.
+	Delete bool
+	// Clause lists the literals of the clause.
+	Clause []clause.Literal
+}
+
+// Proof is a sequence of clause additions and deletions.
+// The methods that record steps do nothing on a nil proof, so engines can record unconditionally.
+type Proof struct {
+	Steps []Step
+}
+
+// Add records the addition of a clause.
+func (p *Proof) Add(c clause.Clause) {
+	if p != nil {
+		p.Steps = append(p.Steps, Step{Clause: c.Literals()})
+	}
+}
+
+// Delete records the deletion of a clause.
+func (p *Proof) Delete(c clause.Clause) {
+	if p != nil {
+		p.Steps = append(p.Steps, Step{Delete: true, Clause: c.Literals()})
+	}
+}
+
+// Write writes the proof in the text format: one clause per line, terminated by 0,
+// deletions prefixed with d.
+//
+// Example: adding {A, -B} and deleting {B} is written as
+//
+//	1 -2 0
+//	d 2 0
+func (p *Proof) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, step := range p.Steps {
+		if step.Delete {
+			fmt.Fprint(bw, "d ")
+		}
+		for _, l := range step.Clause {
+			fmt.Fprintf(bw, "%d ", l)
+		}
+		fmt.Fprintln(bw, "0")
+	}
+	return bw.Flush()
+}
+
+// WriteBinary writes the proof in the binary format: every step starts with the byte 'a' or 'd',
+// followed by its literals and a terminating 0. A literal l is mapped to 2l if positive and to
+// 2|l|+1 if negative, and written as a variable-length number of 7 bits per byte, low bits first.
+func (p *Proof) WriteBinary(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, step := range p.Steps {
+		if step.Delete {
+			bw.WriteByte('d')
+		} else {
+			bw.WriteByte('a')
+		}
+		for _, l := range step.Clause {
+			n := uint(2 * l)
+			if l < 0 {
+				n = uint(-2*l + 1)
+			}
+			for n > 127 {
+				bw.WriteByte(byte(n&127 | 128))
+				n >>= 7
+			}
+			bw.WriteByte(byte(n))
+		}
+		bw.WriteByte(0)
+	}
+	return bw.Flush()
+}
+
+// Read reads a proof in the text format. Lines starting with c are comments.
+func Read(r io.Reader) (*Proof, error) {
+	p := &Proof{}
+	step := Step{Clause: []clause.Literal{}}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(text, "c") {
+			continue
+		}
+		for _, field := range strings.Fields(text) {
+			if field == "d" && len(step.Clause) == 0 && !step.Delete {
+				step.Delete = true
+				continue
+			}
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid literal %q", line, field)
+			}
+			if n == 0 {
+				p.Steps = append(p.Steps, step)
+				step = Step{Clause: []clause.Literal{}}
+				continue
+			}
+			step.Clause = append(step.Clause, clause.Literal(n))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(step.Clause) > 0 || step.Delete {
+		return nil, fmt.Errorf("last clause is not terminated by 0")
+	}
+	return p, nil
+}
+
+// ReadBinary reads a proof in the binary format.
+func ReadBinary(r io.Reader) (*Proof, error) {
+	p := &Proof{}
+	br := bufio.NewReader(r)
+	for {
+		kind, err := br.ReadByte()
+		if err == io.EOF {
+			return p, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if kind != 'a' && kind != 'd' {
+			return nil, fmt.Errorf("step %d: unknown step type %q", len(p.Steps)+1, kind)
+		}
+		step := Step{Delete: kind == 'd', Clause: []clause.Literal{}}
+		for {
+			var n uint
+			for shift := 0; ; shift += 7 {
+				b, err := br.ReadByte()
+				if err != nil {
+					return nil, fmt.Errorf("step %d: last clause is not terminated by 0", len(p.Steps)+1)
+				}
+				n |= uint(b&127) << shift
+				if b < 128 {
+					break
+				}
+			}
+			if n == 0 {
+				break
+			}
+			l := clause.Literal(n / 2)
+			if n%2 == 1 {
+				l = -l
+			}
+			step.Clause = append(step.Clause, l)
+		}
+		p.Steps = append(p.Steps, step)
+	}
+}
+
+// Check verifies a proof of unsatisfiability of the clause set. Every added clause must be a
+// reverse unit propagation (RUP) consequence of the clauses present at that point: assigning
+// all its literals false and propagating unit clauses must falsify a clause. Deleted clauses
+// must be present. The proof must add the empty clause.
+// Returns an error describing the first invalid step.
+func Check(set []clause.Clause, p *Proof) error {
+	clauses := make([][]clause.Literal, 0, len(set)+len(p.Steps))
+	for i := range set {
+		clauses = append(clauses, set[i].Literals())
+	}
+	deleted := make([]bool, len(clauses), cap(clauses))
+	for i, step := range p.Steps {
+		if step.Delete {
+			k := find(clauses, deleted, step.Clause)
+			if k < 0 {
+				return fmt.Errorf("step %d: deleted clause %s is not present", i+1, format(step.Clause))
+			}
+			deleted[k] = true
+			continue
+		}
+		if !rup(clauses, deleted, step.Clause) {
+			return fmt.Errorf("step %d: clause %s is not implied by unit propagation", i+1, format(step.Clause))
+		}
+		if len(step.Clause) == 0 {
+			return nil
+		}
+		clauses = append(clauses, step.Clause)
+		deleted = append(deleted, false)
+	}
+	return fmt.Errorf("the proof does not add the empty clause")
+}
+
+// rup checks if assigning the negation of every literal of c and propagating unit clauses
+// falsifies one of the present clauses.
+func rup(clauses [][]clause.Literal, deleted []bool, c []clause.Literal) bool {
+	t := trail.New()
+	for _, l := range c {
+		value, ok := t.Value(l)
+		if ok && value {
+			// The clause contains l and -l
+			return true
+		}
+		if !ok {
+			t.Assign(-l)
+		}
+	}
+	present := make([][]clause.Literal, 0, len(clauses))
+	for i := range clauses {
+		if !deleted[i] {
+			present = append(present, clauses[i])
+		}
+	}
+	return t.Propagate(present, nil) >= 0
+}
+
+// find returns the index of the first present clause with the same literals as c, or -1.
+func find(clauses [][]clause.Literal, deleted []bool, c []clause.Literal) int {
+	for i, literals := range clauses {
+		if !deleted[i] && same(literals, c) {
+			return i
+		}
+	}
+	return -1
+}
+
+// same checks if two clauses contain the same literals.
+func same(a, b []clause.Literal) bool {
+	seen := map[clause.Literal]bool{}
+	for _, l := range a {
+		seen[l] = true
+	}
+	other := map[clause.Literal]bool{}
+	for _, l := range b {
+		if !seen[l] {
+			return false
+		}
+		other[l] = true
+	}
+	return len(seen) == len(other)
+}
+
+// format returns a clause in DIMACS notation, e.g. "1 -2 0".
+func format(c []clause.Literal) string {
+	s := ""
+	for _, l := range c {
+		s += strconv.Itoa(int(l)) + " "
+	}
+	return s + "0"
+}
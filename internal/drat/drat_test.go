@@ -0,0 +1,130 @@
+package drat
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+func TestWrite(t *testing.T) {
+	p := &Proof{}
+	p.Add(parseClause(t, "A,-B"))
+	p.Delete(parseClause(t, "B"))
+	p.Add(*clause.New())
+	var buf bytes.Buffer
+	if err := p.Write(&buf); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if expected := "1 -2 0\nd 2 0\n0\n"; buf.String() != expected {
+		t.Errorf("Write() = %q; want %q", buf.String(), expected)
+	}
+	read, err := Read(strings.NewReader("c comment\n" + buf.String()))
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	checkSteps(t, read, p)
+}
+
+func TestWriteBinary(t *testing.T) {
+	p := &Proof{Steps: []Step{
+		{Clause: []clause.Literal{1, -2}},
+		{Delete: true, Clause: []clause.Literal{63, -63, 64}},
+		{Clause: []clause.Literal{}},
+	}}
+	var buf bytes.Buffer
+	if err := p.WriteBinary(&buf); err != nil {
+		t.Fatalf("WriteBinary() failed: %v", err)
+	}
+	// 1 -> 2, -2 -> 5, 63 -> 126, -63 -> 127, 64 -> 128 = 0x80 0x01
+	expected := []byte{'a', 2, 5, 0, 'd', 126, 127, 0x80, 0x01, 0, 'a', 0}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("WriteBinary() = %v; want %v", buf.Bytes(), expected)
+	}
+	read, err := ReadBinary(&buf)
+	if err != nil {
+		t.Fatalf("ReadBinary() failed: %v", err)
+	}
+	checkSteps(t, read, p)
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, input := range []string{"1 x 0\n", "1 -2\n", "d\n"} {
+		if _, err := Read(strings.NewReader(input)); err == nil {
+			t.Errorf("Read(%q) succeeded; want error", input)
+		}
+	}
+	for _, input := range [][]byte{{'x', 0}, {'a', 2}, {'a', 0x80}} {
+		if _, err := ReadBinary(bytes.NewReader(input)); err == nil {
+			t.Errorf("ReadBinary(%v) succeeded; want error", input)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	set := []clause.Clause{
+		parseClause(t, "A,B"), parseClause(t, "-A,B"), parseClause(t, "A,-B"), parseClause(t, "-A,-B"),
+	}
+	tests := []struct {
+		name     string
+		proof    string
+		expected string
+	}{
+		{"resolvents", "2 0\n-2 0\n0\n", ""},
+		{"unit propagation only", "2 0\n0\n", ""},
+		{"deletion", "2 0\nd 1 2 0\nd -1 2 0\n0\n", ""},
+		{"not implied", "3 0\n", "step 1: clause 3 0 is not implied by unit propagation"},
+		{"deleted too much", "d 1 2 0\nd -1 2 0\n2 0\n", "step 3: clause 2 0 is not implied by unit propagation"},
+		{"deleted clause missing", "d 1 0\n", "step 1: deleted clause 1 0 is not present"},
+		{"no empty clause", "2 0\n", "the proof does not add the empty clause"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Read(strings.NewReader(tt.proof))
+			if err != nil {
+				t.Fatalf("Read() failed: %v", err)
+			}
+			err = Check(set, p)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Check() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Check() = %v; want %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNilProof(t *testing.T) {
+	var p *Proof
+	p.Add(*clause.New())
+	p.Delete(*clause.New())
+}
+
+// Helper function to parse a clause
+func parseClause(t *testing.T, s string) clause.Clause {
+	t.Helper()
+	c, err := clause.Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", s, err)
+	}
+	return *c
+}
+
+// Helper function to compare the steps of two proofs
+func checkSteps(t *testing.T, result, expected *Proof) {
+	t.Helper()
+	if len(result.Steps) != len(expected.Steps) {
+		t.Fatalf("got %d steps; want %d", len(result.Steps), len(expected.Steps))
+	}
+	for i := range expected.Steps {
+		r, e := result.Steps[i], expected.Steps[i]
+		if r.Delete != e.Delete || format(r.Clause) != format(e.Clause) {
+			t.Errorf("step %d = %+v; want %+v", i, r, e)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package gen generates random and structured clause sets for testing and benchmarking.
+package gen
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+// Random generates a uniform random k-SAT instance with n variables and m clauses.
+// Every clause contains k distinct variables, each negated with probability 1/2.
+// The same seed always generates the same instance.
+func Random(k, n, m int, seed int64) ([]clause.Clause, error) {
+	if err := check(k, n, m); err != nil {
+		return nil, err
+	}
+	r := rand.New(rand.NewSource(seed))
+	set := make([]clause.Clause, m)
+	for i := range set {
+		set[i] = *randomClause(r, k, n)
+	}
+	return set, nil
+}
+
+// Planted generates a random k-SAT instance with n variables and m clauses that is satisfied
+// by a hidden assignment. Clauses falsified by the hidden assignment are discarded and drawn again.
+// Returns the clause set together with the hidden assignment, given as the true literals sorted by variable.
+func Planted(k, n, m int, seed int64) ([]clause.Clause, []clause.Literal, error) {
+	if err := check(k, n, m); err != nil {
+		return nil, nil, err
+	}
+	r := rand.New(rand.NewSource(seed))
+	solution := make([]clause.Literal, n)
+	assignment := map[clause.Literal]bool{}
+	for i := range solution {
+		v := clause.Literal(i + 1)
+		assignment[v] = r.Intn(2) == 0
+		solution[i] = v
+		if !assignment[v] {
+			solution[i] = -v
+		}
+	}
+	set := make([]clause.Clause, m)
+	for i := range set {
+		c := randomClause(r, k, n)
+		for !c.Eval(assignment) {
+			c = randomClause(r, k, n)
+		}
+		set[i] = *c
+	}
+	return set, solution, nil
+}
+
+// Pigeonhole generates the unsatisfiable pigeonhole formula for placing holes+1 pigeons into holes holes.
+// Variable i*holes+j+1 means that pigeon i sits in hole j. Every pigeon sits in some hole,
+// and no two pigeons share a hole.
+func Pigeonhole(holes int) ([]clause.Clause, error) {
+	if holes < 1 || (holes+1)*holes > int(clause.MaxVariable) {
+		return nil, fmt.Errorf("number of holes must be between 1 and %d", maxHoles())
+	}
+	pigeons := holes + 1
+	p := func(i, j int) clause.Literal {
+		return clause.Literal(i*holes + j + 1)
+	}
+	set := []clause.Clause{}
+	for i := 0; i < pigeons; i++ {
+		c := clause.New()
+		for j := 0; j < holes; j++ {
+			c.Insert(p(i, j))
+		}
+		set = append(set, *c)
+	}
+	for j := 0; j < holes; j++ {
+		for i := 0; i < pigeons; i++ {
+			for k := i + 1; k < pigeons; k++ {
+				c := clause.New()
+				c.Insert(-p(i, j))
+				c.Insert(-p(k, j))
+				set = append(set, *c)
+			}
+		}
+	}
+	return set, nil
+}
+
+// Parity generates an unsatisfiable parity formula over n variables.
+// The parity x1 ⊕ ... ⊕ xn is computed twice by chains of auxiliary variables,
+// each chain adding the variables in a different random order. The first chain is
+// required to be true and the second one to be false. The formula uses 3n-2 variables.
+func Parity(n int, seed int64) ([]clause.Clause, error) {
+	if n < 1 || 3*n-2 > int(clause.MaxVariable) {
+		return nil, fmt.Errorf("number of variables must be between 1 and %d", (int(clause.MaxVariable)+2)/3)
+	}
+	r := rand.New(rand.NewSource(seed))
+	set := []clause.Clause{}
+	next := clause.Literal(n + 1)
+	for _, value := range []bool{true, false} {
+		order := r.Perm(n)
+		// The chain starts with the first variable itself
+		acc := clause.Literal(order[0] + 1)
+		for _, i := range order[1:] {
+			x := clause.Literal(i + 1)
+			set = append(set, xor(next, acc, x)...)
+			acc = next
+			next++
+		}
+		unit := clause.New()
+		if value {
+			unit.Insert(acc)
+		} else {
+			unit.Insert(-acc)
+		}
+		set = append(set, *unit)
+	}
+	return set, nil
+}
+
+// xor returns the clauses encoding y ⇔ (a ⊕ b).
+func xor(y, a, b clause.Literal) []clause.Clause {
+	signs := [][3]clause.Literal{
+		{-y, a, b}, {-y, -a, -b}, {y, -a, b}, {y, a, -b},
+	}
+	set := make([]clause.Clause, len(signs))
+	for i, s := range signs {
+		c := clause.New()
+		for _, l := range s {
+			c.Insert(l)
+		}
+		set[i] = *c
+	}
+	return set
+}
+
+// randomClause returns a clause with k distinct variables out of n, each negated with probability 1/2.
+func randomClause(r *rand.Rand, k, n int) *clause.Clause {
+	c := clause.New()
+	for _, v := range r.Perm(n)[:k] {
+		l := clause.Literal(v + 1)
+		if r.Intn(2) == 0 {
+			l = -l
+		}
+		c.Insert(l)
+	}
+	return c
+}
+
+// check validates the parameters of a random k-SAT instance.
+func check(k, n, m int) error {
+	if n < 1 || n > int(clause.MaxVariable) {
+		return fmt.Errorf("number of variables must be between 1 and %d", clause.MaxVariable)
+	}
+	if k < 1 || k > n {
+		return fmt.Errorf("clause width must be between 1 and the number of variables")
+	}
+	if m < 0 {
+		return fmt.Errorf("number of clauses must not be negative")
+	}
+	return nil
+}
+
+// maxHoles returns the largest number of holes whose pigeonhole formula fits into the available variables.
+func maxHoles() int {
+	holes := 1
+	for (holes+2)*(holes+1) <= int(clause.MaxVariable) {
+		holes++
+	}
+	return holes
+}
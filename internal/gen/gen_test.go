@@ -0,0 +1,136 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+func TestRandom(t *testing.T) {
+	set, err := Random(3, 10, 42, 7)
+	if err != nil {
+		t.Fatalf("Random() failed: %v", err)
+	}
+	if len(set) != 42 {
+		t.Errorf("len(Random()) = %d; want 42", len(set))
+	}
+	for i := range set {
+		if set[i].Size() != 3 {
+			t.Errorf("clause %s has %d literals; want 3", set[i].String(), set[i].Size())
+		}
+		for _, l := range set[i].Literals() {
+			if l.Var() < 1 || l.Var() > 10 {
+				t.Errorf("clause %s uses variable outside A..J", set[i].String())
+			}
+		}
+	}
+	again, _ := Random(3, 10, 42, 7)
+	for i := range set {
+		if !set[i].Equals(again[i]) {
+			t.Errorf("Random() is not deterministic for the same seed")
+			break
+		}
+	}
+}
+
+func TestRandomInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		k, n, m int
+	}{
+		{"no variables", 3, 0, 10},
+		{"too many variables", 3, 27, 10},
+		{"zero width", 0, 5, 10},
+		{"width exceeds variables", 4, 3, 10},
+		{"negative clauses", 3, 5, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Random(tt.k, tt.n, tt.m, 1); err == nil {
+				t.Errorf("Random(%d, %d, %d) succeeded; want error", tt.k, tt.n, tt.m)
+			}
+		})
+	}
+}
+
+func TestPlanted(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		set, solution, err := Planted(3, 8, 60, seed)
+		if err != nil {
+			t.Fatalf("Planted() failed: %v", err)
+		}
+		if len(solution) != 8 {
+			t.Fatalf("len(solution) = %d; want 8", len(solution))
+		}
+		assignment := map[clause.Literal]bool{}
+		for _, l := range solution {
+			assignment[l.Var()] = l > 0
+		}
+		for i := range set {
+			if !set[i].Eval(assignment) {
+				t.Errorf("hidden solution %v falsifies %s", solution, set[i].String())
+			}
+		}
+	}
+}
+
+func TestPigeonhole(t *testing.T) {
+	tests := []struct {
+		holes           int
+		expectedClauses int
+	}{
+		{1, 2 + 1},
+		{2, 3 + 2*3},
+		{3, 4 + 3*6},
+	}
+	for _, tt := range tests {
+		set, err := Pigeonhole(tt.holes)
+		if err != nil {
+			t.Fatalf("Pigeonhole(%d) failed: %v", tt.holes, err)
+		}
+		if len(set) != tt.expectedClauses {
+			t.Errorf("len(Pigeonhole(%d)) = %d; want %d", tt.holes, len(set), tt.expectedClauses)
+		}
+		if unsat, _ := clause.BruteForce(set); !unsat {
+			t.Errorf("Pigeonhole(%d) is satisfiable", tt.holes)
+		}
+	}
+	if _, err := Pigeonhole(5); err == nil {
+		t.Errorf("Pigeonhole(5) succeeded; want error for 30 variables")
+	}
+	if maxHoles() != 4 {
+		t.Errorf("maxHoles() = %d; want 4", maxHoles())
+	}
+}
+
+func TestParity(t *testing.T) {
+	for n := 1; n <= 4; n++ {
+		set, err := Parity(n, int64(n))
+		if err != nil {
+			t.Fatalf("Parity(%d) failed: %v", n, err)
+		}
+		if vars := len(clause.Variables(set)); vars != 3*n-2 {
+			t.Errorf("Parity(%d) uses %d variables; want %d", n, vars, 3*n-2)
+		}
+		if unsat, _ := clause.BruteForce(set); !unsat {
+			t.Errorf("Parity(%d) is satisfiable", n)
+		}
+	}
+	if _, err := Parity(10, 1); err == nil {
+		t.Errorf("Parity(10) succeeded; want error for 28 variables")
+	}
+}
+
+func TestXor(t *testing.T) {
+	set := xor(3, 1, 2)
+	clause.Assignments([]clause.Literal{1, 2, 3}, func(assignment map[clause.Literal]bool) bool {
+		satisfied := true
+		for i := range set {
+			satisfied = satisfied && set[i].Eval(assignment)
+		}
+		if expected := assignment[3] == (assignment[1] != assignment[2]); satisfied != expected {
+			t.Errorf("xor clauses under %v = %v; want %v", assignment, satisfied, expected)
+		}
+		return true
+	})
+}
@@ -0,0 +1,162 @@
+// Package horn provides a linear-time decision procedure for Horn and renamable Horn clause sets.
+package horn
+
+import (
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/stats"
+	"github.com/thxrsxm/res/internal/twosat"
+)
+
+// IsHorn checks if every clause of the set contains at most one positive literal.
+func IsHorn(set []clause.Clause) bool {
+	for i := range set {
+		positive := 0
+		for _, l := range set[i].Literals() {
+			if l > 0 {
+				positive++
+			}
+		}
+		if positive > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Solve decides a Horn clause set by forward chaining.
+// It returns true if the clause set is unsatisfiable, otherwise false together with
+// its least model, given as the literals that are true (sorted by variable).
+//
+// Every clause is read as an implication from its negative literals (the body)
+// to its positive literal (the head). Starting from the facts, every head whose
+// body is fully true is set to true. The clause set is unsatisfiable if a clause
+// without a head gets a true body.
+//
+// The clause set must be Horn (see IsHorn).
+// If st is not nil, every variable set to true is recorded as a propagation.
+func Solve(set []clause.Clause, st *stats.Stats) (bool, []clause.Literal) {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	heads := make([]clause.Literal, len(set))
+	remaining := make([]int, len(set))
+	// bodies maps every variable to the clauses containing its negation
+	bodies := map[clause.Literal][]int{}
+	queue := []clause.Literal{}
+	for i := range set {
+		if set[i].IsEmpty() {
+			return true, nil
+		}
+		for _, l := range set[i].Literals() {
+			if l > 0 {
+				heads[i] = l
+			} else {
+				remaining[i]++
+				bodies[-l] = append(bodies[-l], i)
+			}
+		}
+		if remaining[i] == 0 {
+			queue = append(queue, heads[i])
+		}
+	}
+	truth := map[clause.Literal]bool{}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		if truth[v] {
+			continue
+		}
+		truth[v] = true
+		st.Propagations++
+		for _, i := range bodies[v] {
+			remaining[i]--
+			if remaining[i] > 0 {
+				continue
+			}
+			if heads[i] == clause.ErrorLiteral {
+				return true, nil
+			}
+			queue = append(queue, heads[i])
+		}
+	}
+	model := []clause.Literal{}
+	for _, v := range clause.Variables(set) {
+		if truth[v] {
+			model = append(model, v)
+		} else {
+			model = append(model, -v)
+		}
+	}
+	return false, model
+}
+
+// SolveRenamable decides a renamable Horn clause set.
+// A clause set is renamable Horn if flipping the sign of some variables turns it into a Horn set.
+// The last return value is false if no such renaming exists.
+// Otherwise the clause set is solved with Solve and the model is mapped back to the original variables.
+func SolveRenamable(set []clause.Clause, st *stats.Stats) (bool, []clause.Literal, bool) {
+	flips, ok := Renaming(set)
+	if !ok {
+		return false, nil, false
+	}
+	flipped := map[clause.Literal]bool{}
+	for _, v := range flips {
+		flipped[v] = true
+	}
+	renamed := make([]clause.Clause, len(set))
+	for i := range set {
+		c := clause.New()
+		for _, l := range set[i].Literals() {
+			c.Insert(rename(l, flipped))
+		}
+		renamed[i] = *c
+	}
+	unsat, model := Solve(renamed, st)
+	if unsat {
+		return true, nil, true
+	}
+	for i := range model {
+		model[i] = rename(model[i], flipped)
+	}
+	return false, model, true
+}
+
+// Renaming returns the variables whose sign has to be flipped to turn the clause set into a Horn set.
+// Returns false if the clause set is not renamable Horn.
+//
+// After renaming, no two literals of a clause may be positive. Writing a flipped
+// variable as a true literal, this is exactly the 2-CNF containing (l1 ∨ l2) for
+// every pair of literals l1, l2 of the same clause, which is decided with twosat.Solve.
+func Renaming(set []clause.Clause) ([]clause.Literal, bool) {
+	pairs := []clause.Clause{}
+	for i := range set {
+		literals := set[i].Literals()
+		for a := 0; a < len(literals); a++ {
+			for b := a + 1; b < len(literals); b++ {
+				c := clause.New()
+				c.Insert(literals[a])
+				c.Insert(literals[b])
+				pairs = append(pairs, *c)
+			}
+		}
+	}
+	unsat, model, _ := twosat.Solve(pairs, nil)
+	if unsat {
+		return nil, false
+	}
+	flips := []clause.Literal{}
+	for _, l := range model {
+		if l > 0 {
+			flips = append(flips, l)
+		}
+	}
+	return flips, true
+}
+
+// rename flips the sign of l if its variable is flipped.
+func rename(l clause.Literal, flipped map[clause.Literal]bool) clause.Literal {
+	if flipped[l.Var()] {
+		return -l
+	}
+	return l
+}
@@ -0,0 +1,147 @@
+package horn
+
+import (
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+func TestIsHorn(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		expected bool
+	}{
+		{"empty clause set", []string{}, true},
+		{"facts", []string{"A", "B"}, true},
+		{"rules", []string{"-A,-B,C", "-C,D"}, true},
+		{"goal clause", []string{"-A,-B"}, true},
+		{"two positive literals", []string{"A,B"}, false},
+		{"one non-Horn clause", []string{"A", "-A,B,C"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsHorn(clausetest.ParseSet(t, tt.clauses...))
+			if result != tt.expected {
+				t.Errorf("IsHorn(%v) = %v; want %v", tt.clauses, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name          string
+		clauses       []string
+		expected      bool
+		expectedModel string
+	}{
+		{"empty clause set", []string{}, false, "{}"},
+		{"facts", []string{"A", "C"}, false, "{A, C}"},
+		{"no facts", []string{"-A,B", "-B,-C"}, false, "{-A, -B, -C}"},
+		{"forward chaining", []string{"A", "-A,B", "-B,-D,C"}, false, "{A, B, -C, -D}"},
+		{"conjunctive body", []string{"A", "B", "-A,-B,C"}, false, "{A, B, C}"},
+		{"goal violated", []string{"A", "-A,B", "-B"}, true, ""},
+		{"conjunctive goal violated", []string{"A", "B", "-A,-B"}, true, ""},
+		{"simple contradiction", []string{"A", "-A"}, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, model := Solve(clausetest.ParseSet(t, tt.clauses...), nil)
+			if result != tt.expected {
+				t.Fatalf("Solve(%v) = %v; want %v", tt.clauses, result, tt.expected)
+			}
+			if expected := clause.Res(clausetest.ParseSet(t, tt.clauses...), 0); result != expected {
+				t.Errorf("Solve(%v) = %v; clause.Res = %v", tt.clauses, result, expected)
+			}
+			if !result && formatModel(model) != tt.expectedModel {
+				t.Errorf("Solve(%v) model = %s; want %s", tt.clauses, formatModel(model), tt.expectedModel)
+			}
+		})
+	}
+}
+
+func TestSolveEmptyClause(t *testing.T) {
+	result, model := Solve([]clause.Clause{*clause.New()}, nil)
+	if !result || model != nil {
+		t.Errorf("Solve() = %v, %v; want true, nil", result, model)
+	}
+}
+
+func TestSolveStats(t *testing.T) {
+	st := &stats.Stats{}
+	Solve(clausetest.ParseSet(t, "A", "-A,B", "-B,C", "-D,E"), st)
+	if st.Propagations != 3 {
+		t.Errorf("Propagations = %d; want 3 for A, B and C", st.Propagations)
+	}
+}
+
+func TestSolveRenamable(t *testing.T) {
+	tests := []struct {
+		name          string
+		clauses       []string
+		expected      bool
+		expectedOk    bool
+		expectedModel string
+	}{
+		{"already Horn", []string{"A", "-A,B"}, false, true, "{A, B}"},
+		{"flipped Horn", []string{"A,B", "-A"}, false, true, "{-A, B}"},
+		{"flipped unsatisfiable", []string{"A,B", "-A", "-B"}, true, true, ""},
+		{"not renamable", []string{"A,B", "-A,-B", "A,-B", "-A,B"}, false, false, ""},
+		{"not renamable three literals", []string{"A,B,C", "-A,-B,-C"}, false, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, model, ok := SolveRenamable(clausetest.ParseSet(t, tt.clauses...), nil)
+			if ok != tt.expectedOk {
+				t.Fatalf("SolveRenamable(%v) ok = %v; want %v", tt.clauses, ok, tt.expectedOk)
+			}
+			if !ok {
+				return
+			}
+			if result != tt.expected {
+				t.Fatalf("SolveRenamable(%v) = %v; want %v", tt.clauses, result, tt.expected)
+			}
+			if expected := clause.Res(clausetest.ParseSet(t, tt.clauses...), 0); result != expected {
+				t.Errorf("SolveRenamable(%v) = %v; clause.Res = %v", tt.clauses, result, expected)
+			}
+			if !result && formatModel(model) != tt.expectedModel {
+				t.Errorf("SolveRenamable(%v) model = %s; want %s", tt.clauses, formatModel(model), tt.expectedModel)
+			}
+		})
+	}
+}
+
+func TestRenaming(t *testing.T) {
+	set := clausetest.ParseSet(t, "A,B,-C", "-A,D", "B,D")
+	flips, ok := Renaming(set)
+	if !ok {
+		t.Fatalf("Renaming() = false; want true")
+	}
+	flipped := map[clause.Literal]bool{}
+	for _, v := range flips {
+		flipped[v] = true
+	}
+	renamed := []clause.Clause{}
+	for i := range set {
+		c := clause.New()
+		for _, l := range set[i].Literals() {
+			c.Insert(rename(l, flipped))
+		}
+		renamed = append(renamed, *c)
+	}
+	if !IsHorn(renamed) {
+		t.Errorf("renaming %v does not produce a Horn set: %v", flips, renamed)
+	}
+}
+
+// Helper function to format a model in set notation
+func formatModel(model []clause.Literal) string {
+	c := clause.New()
+	for _, l := range model {
+		c.Insert(l)
+	}
+	return c.String()
+}
@@ -0,0 +1,286 @@
+// Package incremental implements a solver for a clause set that grows between queries.
+//
+// Every query decides the clause set under assumptions, literals that are taken to be true for
+// this query only. The solver is a DPLL search that learns a clause from every conflict by
+// resolving the falsified clause with the clauses that propagated its literals, until only the
+// negations of decisions remain. Learnt clauses follow from the clause set alone, so they are
+// kept and speed up later queries. If the clause set is unsatisfiable under the assumptions,
+// the last learnt clause names the assumptions that are to blame.
+//
+// Clauses can be added in nested scopes. Every clause belongs to the innermost scope open when it
+// was added, every learnt clause to the innermost scope of the clauses it was resolved from.
+// Closing a scope removes its clauses, so learnt clauses never outlive the clauses they follow from.
+package incremental
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/stats"
+	"github.com/thxrsxm/res/internal/trail"
+)
+
+// Result is the outcome of a query.
+type Result struct {
+	// Unsatisfiable is true if no assignment satisfies the clauses and the assumptions.
+	Unsatisfiable bool
+	// Model lists the true literals of a satisfying assignment of every variable of the clauses
+	// and the assumptions, sorted by variable. It is nil if Unsatisfiable is true.
+	Model []clause.Literal
+	// Failed lists the assumptions, in the order given, that together with the clauses are
+	// unsatisfiable. It is empty if the clauses are unsatisfiable on their own.
+	Failed []clause.Literal
+}
+
+// entry describes a clause of the solver.
+type entry struct {
+	// learnt is true for clauses derived by the solver
+	learnt bool
+	// scope is the depth of the scope the clause belongs to, 0 for clauses outside every scope
+	scope int
+}
+
+// lemma is a clause derived by resolution together with the innermost scope of its premises.
+type lemma struct {
+	clause clause.Clause
+	scope  int
+}
+
+// Solver holds a clause set and the clauses learnt from it.
+type Solver struct {
+	// clauses lists the literals of every clause, entries describes the clause with the same index
+	clauses [][]clause.Literal
+	entries []entry
+	// scope is the number of open scopes
+	scope int
+	// trail holds the assignment of the current query and the clauses that propagated it
+	trail *trail.Trail
+	// order lists the variables of the clauses in the order they are decided
+	order []clause.Literal
+	stats *stats.Stats
+}
+
+// New creates a solver without clauses.
+func New() *Solver {
+	return &Solver{}
+}
+
+// Add adds clauses to the solver.
+func (s *Solver) Add(set ...clause.Clause) {
+	for i := range set {
+		s.clauses = append(s.clauses, set[i].Literals())
+		s.entries = append(s.entries, entry{scope: s.scope})
+	}
+}
+
+// Push opens a scope. Clauses added until the matching Pop belong to it.
+func (s *Solver) Push() {
+	s.scope++
+}
+
+// Pop closes the innermost scope and removes its clauses together with the clauses learnt from them.
+// It returns an error if no scope is open.
+func (s *Solver) Pop() error {
+	if s.scope == 0 {
+		return fmt.Errorf("no scope to pop")
+	}
+	clauses := [][]clause.Literal{}
+	entries := []entry{}
+	for i, e := range s.entries {
+		if e.scope < s.scope {
+			clauses = append(clauses, s.clauses[i])
+			entries = append(entries, e)
+		}
+	}
+	s.clauses = clauses
+	s.entries = entries
+	s.scope--
+	return nil
+}
+
+// Scopes returns the number of open scopes.
+func (s *Solver) Scopes() int {
+	return s.scope
+}
+
+// Clauses returns the clauses added to the solver in order.
+func (s *Solver) Clauses() []clause.Clause {
+	return s.collect(false)
+}
+
+// Learnt returns the clauses learnt by earlier queries in order.
+func (s *Solver) Learnt() []clause.Clause {
+	return s.collect(true)
+}
+
+// collect returns the added or the learnt clauses.
+func (s *Solver) collect(learnt bool) []clause.Clause {
+	set := []clause.Clause{}
+	for i, e := range s.entries {
+		if e.learnt == learnt {
+			set = append(set, toClause(s.clauses[i]))
+		}
+	}
+	return set
+}
+
+// Solve decides the clauses under the assumptions. It returns ctx.Err() if the context is done
+// before the search ends; the clauses learnt until then are kept.
+// If st is not nil, decisions, conflicts and propagations are recorded.
+func (s *Solver) Solve(ctx context.Context, assumptions []clause.Literal, st *stats.Stats) (Result, error) {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	s.stats = st
+	s.trail = trail.New()
+	s.order = s.variables()
+	if conflict := s.trail.Propagate(s.clauses, s.stats); conflict >= 0 {
+		s.learn(s.analyze(conflict))
+		return Result{Unsatisfiable: true, Failed: []clause.Literal{}}, nil
+	}
+	for _, a := range assumptions {
+		value, ok := s.trail.Value(a)
+		if ok && value {
+			continue
+		}
+		if ok {
+			return s.contradict(assumptions, a), nil
+		}
+		s.trail.Assign(a)
+		if conflict := s.trail.Propagate(s.clauses, s.stats); conflict >= 0 {
+			return s.fail(assumptions, s.analyze(conflict)), nil
+		}
+	}
+	l, err := s.search(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	if l != nil {
+		return s.fail(assumptions, *l), nil
+	}
+	return Result{Model: s.trail.Model()}, nil
+}
+
+// fail returns the result for a lemma that consists of negated assumptions.
+func (s *Solver) fail(assumptions []clause.Literal, l lemma) Result {
+	s.learn(l)
+	c := l.clause
+	failed := []clause.Literal{}
+	seen := map[clause.Literal]bool{}
+	for _, a := range assumptions {
+		if c.Contains(-a) && !seen[a] {
+			failed = append(failed, a)
+			seen[a] = true
+		}
+	}
+	return Result{Unsatisfiable: true, Failed: failed}
+}
+
+// contradict returns the result for an assumption a that is false when it is assumed.
+func (s *Solver) contradict(assumptions []clause.Literal, a clause.Literal) Result {
+	r, ok := s.trail.Reason(a)
+	if ok {
+		// The reason of -a, resolved down to negated assumptions, contains -a
+		return s.fail(assumptions, s.analyze(r))
+	}
+	// -a is assumed as well
+	failed := []clause.Literal{}
+	seen := map[clause.Literal]bool{}
+	for _, b := range assumptions {
+		if b.Var() == a.Var() && !seen[b] {
+			failed = append(failed, b)
+			seen[b] = true
+		}
+	}
+	return Result{Unsatisfiable: true, Failed: failed}
+}
+
+// search runs the DPLL procedure from the current assignment.
+// It returns nil if the assignment can be extended to a model, otherwise a lemma consisting of
+// negated decisions made before the search, which is learnt by the caller.
+func (s *Solver) search(ctx context.Context) (*lemma, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if conflict := s.trail.Propagate(s.clauses, s.stats); conflict >= 0 {
+		l := s.analyze(conflict)
+		return &l, nil
+	}
+	v := s.trail.Pick(s.order)
+	if v == clause.ErrorLiteral {
+		return nil, nil
+	}
+	mark := s.trail.Len()
+	s.stats.Decisions++
+	s.trail.Assign(v)
+	l, err := s.search(ctx)
+	if l == nil || err != nil {
+		return l, err
+	}
+	s.trail.Undo(mark)
+	if !l.clause.Contains(-v) {
+		// The conflict does not depend on v, so -v fails as well
+		return l, nil
+	}
+	// The learnt clause propagates -v
+	s.learn(*l)
+	return s.search(ctx)
+}
+
+// analyze resolves the clause with the given index with the reasons of its propagated literals,
+// latest first, until only negated decisions are left. Except for the literal it propagated,
+// the clause must be falsified by the current assignment; so is the result.
+func (s *Solver) analyze(index int) lemma {
+	c := toClause(s.clauses[index])
+	scope := s.entries[index].scope
+	literals := s.trail.Literals()
+	for i := len(literals) - 1; i >= 0; i-- {
+		l := literals[i]
+		r, ok := s.trail.Reason(l)
+		if !ok || !c.Contains(-l) {
+			continue
+		}
+		resolvent, _ := c.Resolve(toClause(s.clauses[r]))
+		c = *resolvent
+		scope = max(scope, s.entries[r].scope)
+	}
+	return lemma{c, scope}
+}
+
+// learn adds a lemma unless the solver already has its clause in the same or an outer scope.
+func (s *Solver) learn(l lemma) {
+	for i, e := range s.entries {
+		if e.scope <= l.scope && l.clause.Equals(toClause(s.clauses[i])) {
+			return
+		}
+	}
+	s.clauses = append(s.clauses, l.clause.Literals())
+	s.entries = append(s.entries, entry{learnt: true, scope: l.scope})
+}
+
+// variables returns the variables of the clauses in ascending order.
+func (s *Solver) variables() []clause.Literal {
+	seen := map[clause.Literal]bool{}
+	for _, c := range s.clauses {
+		for _, l := range c {
+			seen[l.Var()] = true
+		}
+	}
+	vars := []clause.Literal{}
+	for v := clause.Literal(1); v <= clause.MaxVariable; v++ {
+		if seen[v] {
+			vars = append(vars, v)
+		}
+	}
+	return vars
+}
+
+// toClause converts literals into a clause.
+func toClause(literals []clause.Literal) clause.Clause {
+	c := clause.New()
+	for _, l := range literals {
+		c.Insert(l)
+	}
+	return *c
+}
@@ -0,0 +1,329 @@
+package incremental
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+	"github.com/thxrsxm/res/internal/gen"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		expected bool
+	}{
+		{"empty clause set", []string{}, false},
+		{"empty clause", []string{"A", "B,-B"}, true},
+		{"simple contradiction", []string{"A", "-A"}, true},
+		{"requires resolution", []string{"A,B", "-A,B", "A,-B", "-A,-B"}, true},
+		{"satisfiable", []string{"A,B", "-A,C", "B,-C"}, false},
+		{"requires backtracking", []string{"-A,B", "-A,-B", "A,C", "A,-C,D", "-D,-C"}, true},
+		{"three literals", []string{"A,B,C", "-A,-B,-C", "A,-B", "B,-C"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := clausetest.ParseSet(t, tt.clauses...)
+			s := New()
+			s.Add(set...)
+			result, err := s.Solve(context.Background(), nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Unsatisfiable != tt.expected {
+				t.Fatalf("Solve(%v) = %v; want %v", tt.clauses, result.Unsatisfiable, tt.expected)
+			}
+			if result.Unsatisfiable && len(result.Failed) != 0 {
+				t.Errorf("Solve(%v) failed assumptions = %v; want none", tt.clauses, result.Failed)
+			}
+			if !result.Unsatisfiable && !clausetest.Satisfies(set, result.Model) {
+				t.Errorf("Solve(%v) model = %v does not satisfy the clause set", tt.clauses, result.Model)
+			}
+			checkLearnt(t, s)
+		})
+	}
+}
+
+func TestAssumptions(t *testing.T) {
+	s := New()
+	s.Add(clausetest.ParseSet(t, "-A,B", "-B,C", "D,E")...)
+	tests := []struct {
+		assumptions []clause.Literal
+		expected    bool
+		failed      []clause.Literal
+	}{
+		{[]clause.Literal{1}, false, nil},
+		{[]clause.Literal{1, -3}, true, []clause.Literal{1, -3}},
+		{[]clause.Literal{-3, 4, 1}, true, []clause.Literal{-3, 1}},
+		{[]clause.Literal{-3, 1, 4}, true, []clause.Literal{-3, 1}},
+		{[]clause.Literal{2, 5, -2}, true, []clause.Literal{2, -2}},
+		{[]clause.Literal{-4, -5}, true, []clause.Literal{-4, -5}},
+		{[]clause.Literal{-3}, false, nil},
+	}
+	for _, tt := range tests {
+		result, err := s.Solve(context.Background(), tt.assumptions, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Unsatisfiable != tt.expected {
+			t.Errorf("Solve(%v) = %v; want %v", tt.assumptions, result.Unsatisfiable, tt.expected)
+			continue
+		}
+		if tt.expected && !reflect.DeepEqual(result.Failed, tt.failed) {
+			t.Errorf("Solve(%v) failed assumptions = %v; want %v", tt.assumptions, result.Failed, tt.failed)
+		}
+		if !tt.expected {
+			for _, a := range tt.assumptions {
+				if !contains(result.Model, a) {
+					t.Errorf("Solve(%v) model = %v does not contain %d", tt.assumptions, result.Model, a)
+				}
+			}
+		}
+	}
+	checkLearnt(t, s)
+}
+
+func TestLearntClausesAreKept(t *testing.T) {
+	s := New()
+	s.Add(clausetest.ParseSet(t, "A,B,C", "A,B,-C", "A,-B,D", "A,-B,-D")...)
+	first := stats.Stats{}
+	if _, err := s.Solve(context.Background(), []clause.Literal{-1}, &first); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Learnt()) == 0 {
+		t.Fatalf("no clause learnt from a failed query")
+	}
+	second := stats.Stats{}
+	result, err := s.Solve(context.Background(), []clause.Literal{-1}, &second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Unsatisfiable || !reflect.DeepEqual(result.Failed, []clause.Literal{-1}) {
+		t.Errorf("second query = %v; want unsatisfiable with failed assumption -1", result)
+	}
+	if first.Decisions == 0 || second.Decisions != 0 {
+		t.Errorf("queries made %d and %d decisions; want some for the first and none for the second", first.Decisions, second.Decisions)
+	}
+	checkLearnt(t, s)
+}
+
+func TestAdd(t *testing.T) {
+	s := New()
+	s.Add(clausetest.ParseSet(t, "A,B")...)
+	for _, c := range []string{"-A", "-B,C", "-C"} {
+		result, err := s.Solve(context.Background(), nil, nil)
+		if err != nil || result.Unsatisfiable {
+			t.Fatalf("Solve() before adding %s = %v, %v; want satisfiable", c, result, err)
+		}
+		s.Add(clausetest.ParseSet(t, c)...)
+	}
+	result, err := s.Solve(context.Background(), nil, nil)
+	if err != nil || !result.Unsatisfiable {
+		t.Errorf("Solve() = %v, %v; want unsatisfiable", result, err)
+	}
+	if got := len(s.Clauses()); got != 4 {
+		t.Errorf("Clauses() has %d clauses; want 4", got)
+	}
+}
+
+func TestAgreesWithBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for seed := int64(0); seed < 20; seed++ {
+		set, err := gen.Random(3, 5, 12, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := New()
+		for i := range set {
+			s.Add(set[i])
+			for query := 0; query < 3; query++ {
+				assumptions := []clause.Literal{}
+				for k := r.Intn(4); k > 0; k-- {
+					l := clause.Literal(r.Intn(5) + 1)
+					if r.Intn(2) == 0 {
+						l = -l
+					}
+					assumptions = append(assumptions, l)
+				}
+				result, err := s.Solve(context.Background(), assumptions, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				base := set[:i+1]
+				expected, _ := clause.BruteForce(append(units(assumptions), base...))
+				if result.Unsatisfiable != expected {
+					t.Fatalf("seed %d: Solve(%v) with %d clauses = %v; want %v", seed, assumptions, i+1, result.Unsatisfiable, expected)
+				}
+				if !result.Unsatisfiable {
+					if !clausetest.Satisfies(base, result.Model) || !clausetest.Satisfies(units(assumptions), result.Model) {
+						t.Errorf("seed %d: model %v does not satisfy the clauses and %v", seed, result.Model, assumptions)
+					}
+					continue
+				}
+				if unsat, _ := clause.BruteForce(append(units(result.Failed), base...)); !unsat {
+					t.Errorf("seed %d: failed assumptions %v of %v are satisfiable", seed, result.Failed, assumptions)
+				}
+			}
+		}
+		checkLearnt(t, s)
+	}
+}
+
+func TestSolveCancelled(t *testing.T) {
+	s := New()
+	s.Add(clausetest.ParseSet(t, "A,B", "-A,B", "A,-B", "-A,-B")...)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Solve(ctx, nil, nil); err != context.Canceled {
+		t.Errorf("Solve with a cancelled context returned %v; want %v", err, context.Canceled)
+	}
+	result, err := s.Solve(context.Background(), nil, nil)
+	if err != nil || !result.Unsatisfiable {
+		t.Errorf("Solve after cancelling = %v, %v; want unsatisfiable", result, err)
+	}
+}
+
+// checkLearnt checks that every learnt clause follows from the added clauses.
+func checkLearnt(t *testing.T, s *Solver) {
+	t.Helper()
+	for _, c := range s.Learnt() {
+		if unsat, _ := clause.BruteForce(append(units(negate(c.Literals())), s.Clauses()...)); !unsat {
+			t.Errorf("learnt clause %s does not follow from the clauses", c.String())
+		}
+	}
+}
+
+func negate(literals []clause.Literal) []clause.Literal {
+	negated := make([]clause.Literal, len(literals))
+	for i, l := range literals {
+		negated[i] = -l
+	}
+	return negated
+}
+
+// units returns a unit clause for every literal.
+func units(literals []clause.Literal) []clause.Clause {
+	set := []clause.Clause{}
+	for _, l := range literals {
+		c := clause.New()
+		c.Insert(l)
+		set = append(set, *c)
+	}
+	return set
+}
+
+func contains(literals []clause.Literal, l clause.Literal) bool {
+	for _, k := range literals {
+		if k == l {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPushPop(t *testing.T) {
+	s := New()
+	s.Add(clausetest.ParseSet(t, "A,B", "-A,C")...)
+	if err := s.Pop(); err == nil {
+		t.Errorf("Pop() without scope returned no error")
+	}
+	s.Push()
+	s.Add(clausetest.ParseSet(t, "-B", "-C")...)
+	result, err := s.Solve(context.Background(), nil, nil)
+	if err != nil || !result.Unsatisfiable {
+		t.Fatalf("Solve() in scope = %v, %v; want unsatisfiable", result, err)
+	}
+	if len(s.Learnt()) == 0 {
+		t.Fatalf("no clause learnt in scope")
+	}
+	s.Push()
+	s.Add(clausetest.ParseSet(t, "D")...)
+	if s.Scopes() != 2 {
+		t.Errorf("Scopes() = %d; want 2", s.Scopes())
+	}
+	if err := s.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(s.Clauses()); got != 2 {
+		t.Errorf("Clauses() after Pop has %d clauses; want 2", got)
+	}
+	// The empty clause learnt in the scope depends on its clauses and is gone
+	result, err = s.Solve(context.Background(), nil, nil)
+	if err != nil || result.Unsatisfiable {
+		t.Errorf("Solve() after Pop = %v, %v; want satisfiable", result, err)
+	}
+	checkLearnt(t, s)
+}
+
+func TestPopKeepsLearntClausesOfOuterScopes(t *testing.T) {
+	s := New()
+	s.Add(clausetest.ParseSet(t, "A,B,C", "A,B,-C", "A,-B,D", "A,-B,-D")...)
+	if _, err := s.Solve(context.Background(), []clause.Literal{-1}, nil); err != nil {
+		t.Fatal(err)
+	}
+	learnt := len(s.Learnt())
+	s.Push()
+	s.Add(clausetest.ParseSet(t, "-A,E")...)
+	if _, err := s.Solve(context.Background(), []clause.Literal{-5}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Learnt()) <= learnt {
+		t.Fatalf("no clause learnt from the clause of the scope")
+	}
+	if err := s.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(s.Learnt()); got != learnt {
+		t.Errorf("Learnt() after Pop has %d clauses; want %d", got, learnt)
+	}
+	checkLearnt(t, s)
+}
+
+func TestScopesAgreeWithBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for seed := int64(0); seed < 20; seed++ {
+		set, err := gen.Random(3, 5, 14, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := New()
+		// added holds the number of clauses added before each open scope
+		added := []int{}
+		n := 0
+		for n < len(set) {
+			switch r.Intn(4) {
+			case 0:
+				s.Push()
+				added = append(added, n)
+			case 1:
+				if len(added) > 0 {
+					if err := s.Pop(); err != nil {
+						t.Fatal(err)
+					}
+					// The clauses of the scope are added again later
+					n = added[len(added)-1]
+					added = added[:len(added)-1]
+				}
+			default:
+				s.Add(set[n])
+				n++
+			}
+			result, err := s.Solve(context.Background(), nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected, _ := clause.BruteForce(set[:n]); result.Unsatisfiable != expected {
+				t.Fatalf("seed %d: Solve() with %d clauses = %v; want %v", seed, n, result.Unsatisfiable, expected)
+			}
+			checkLearnt(t, s)
+		}
+	}
+}
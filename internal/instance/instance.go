@@ -0,0 +1,86 @@
+// Package instance loads clause sets from files.
+package instance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/dimacs"
+)
+
+// Load reads a clause set from a file.
+// Files with the extension .cnf are read in DIMACS format, every other file in clause syntax (see Read).
+func Load(path string) ([]clause.Clause, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if strings.EqualFold(filepath.Ext(path), ".cnf") {
+		return dimacs.Read(f)
+	}
+	return Read(f)
+}
+
+// Save writes a clause set to a file, creating or truncating it.
+// Files with the extension .cnf are written in DIMACS format, every other file in clause syntax (see Write).
+func Save(path string, set []clause.Clause) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".cnf") {
+		err = dimacs.Write(f, set)
+	} else {
+		err = Write(f, set)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Write writes a clause set in clause syntax, one clause per line.
+// The empty clause has no literals to write, so it is written as A,-A, which Read parses back into the empty clause.
+func Write(w io.Writer, set []clause.Clause) error {
+	bw := bufio.NewWriter(w)
+	for i := range set {
+		literals := set[i].Literals()
+		if len(literals) == 0 {
+			fmt.Fprintln(bw, "A,-A # empty clause")
+			continue
+		}
+		s := make([]string, len(literals))
+		for k, l := range literals {
+			s[k] = clause.Lit2Str(l)
+		}
+		fmt.Fprintln(bw, strings.Join(s, ","))
+	}
+	return bw.Flush()
+}
+
+// Read reads a clause set in clause syntax: clauses in the format A,B,-C separated by whitespace,
+// usually one clause per line. Everything after a # up to the end of the line is a comment.
+func Read(r io.Reader) ([]clause.Clause, error) {
+	set := []clause.Clause{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		for _, field := range strings.Fields(text) {
+			c, err := clause.Parse(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: parsing clause %q: %v", line, field, err)
+			}
+			set = append(set, *c)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
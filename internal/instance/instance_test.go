@@ -0,0 +1,107 @@
+package instance
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []string
+		expectError bool
+	}{
+		{"empty input", "", []string{}, false},
+		{"one clause per line", "A,B\n-A,C\n", []string{"A,B", "-A,C"}, false},
+		{"several clauses per line", "A,B -A  -B\n", []string{"A,B", "-A", "-B"}, false},
+		{"comments", "# header\nA,B # first\n\n-A\n", []string{"A,B", "-A"}, false},
+		{"invalid clause", "A,B\nA,1\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Read(strings.NewReader(tt.input))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Read(%q) succeeded; want error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read(%q) failed: %v", tt.input, err)
+			}
+			checkSet(t, result, tt.expected)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt": "A,-B\nB\n",
+		"b.cnf": "p cnf 2 2\n1 -2 0\n2 0\n",
+		"c.CNF": "1 -2 0\n2 0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name := range files {
+		result, err := Load(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Load(%q) failed: %v", name, err)
+		}
+		checkSet(t, result, []string{"A,-B", "B"})
+	}
+	if _, err := Load(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Errorf("Load() of a missing file succeeded; want error")
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	set := []clause.Clause{}
+	for _, s := range []string{"A,-B", "B", "C,-C"} {
+		c, _ := clause.Parse(s)
+		set = append(set, *c)
+	}
+	for _, name := range []string{"a.txt", "b.cnf"} {
+		path := filepath.Join(dir, name)
+		if err := Save(path, set); err != nil {
+			t.Fatalf("Save(%q) failed: %v", name, err)
+		}
+		result, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%q) failed: %v", name, err)
+		}
+		if len(result) != 3 || !result[2].IsEmpty() {
+			t.Fatalf("Load(%q) = %v; want the saved clauses ending with the empty clause", name, result)
+		}
+		checkSet(t, result[:2], []string{"A,-B", "B"})
+	}
+	if err := Save(filepath.Join(dir, "missing", "a.txt"), set); err == nil {
+		t.Errorf("Save() into a missing directory succeeded; want error")
+	}
+}
+
+// Helper function to compare a clause set with the expected clauses
+func checkSet(t *testing.T, result []clause.Clause, expected []string) {
+	t.Helper()
+	if len(result) != len(expected) {
+		t.Fatalf("got %d clauses %v; want %v", len(result), result, expected)
+	}
+	for i, s := range expected {
+		c, err := clause.Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", s, err)
+		}
+		if !result[i].Equals(*c) {
+			t.Errorf("clause %d = %s; want %s", i, result[i].String(), c.String())
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package lrat writes and checks resolution refutations in the LRAT format,
+// a clausal proof format in which every added clause carries hints: the clauses
+// that unit propagation has to use to derive it.
+//
+// The input clauses are numbered from 1 in order and are not part of the proof.
+// Every line of the proof adds a clause:
+//
+//	id literals 0 hints 0
+//
+// Literals use the DIMACS numbering: A is 1, B is 2, and a negative number is a negated variable.
+package lrat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+// Line adds a clause to the proof.
+type Line struct {
+	// ID is the number of the added clause. It is larger than every earlier number.
+	ID int
+	// Clause lists the literals of the added clause.
+	Clause []clause.Literal
+	// Hints are the numbers of the clauses that become unit, in propagation order,
+	// ending with the clause that is falsified.
+	Hints []int
+}
+
+// FromRefutation converts a refutation of the set, as returned by clause.Refute, into LRAT lines.
+// The resolvent of a step is derived from its parents: with the resolvent false, the left parent
+// becomes unit on the pivot and the right parent is falsified.
+func FromRefutation(set []clause.Clause, steps []clause.Step) []Line {
+	lines := []Line{}
+	if len(steps) == 0 {
+		// The set contains the empty clause, which is falsified right away
+		if i := clause.Index(set, *clause.New()); i >= 0 {
+			lines = append(lines, Line{ID: len(set) + 1, Clause: []clause.Literal{}, Hints: []int{i + 1}})
+		}
+		return lines
+	}
+	for i, step := range steps {
+		lines = append(lines, Line{
+			ID:     len(set) + i + 1,
+			Clause: step.Resolvent.Literals(),
+			Hints:  []int{step.Left + 1, step.Right + 1},
+		})
+	}
+	return lines
+}
+
+// Write writes the lines of a proof.
+//
+// Example: resolving {A, B} (1) and {-A, B} (2) to {B} is written as
+//
+//	3 2 0 1 2 0
+func Write(w io.Writer, lines []Line) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range lines {
+		fmt.Fprintf(bw, "%d ", line.ID)
+		for _, l := range line.Clause {
+			fmt.Fprintf(bw, "%d ", l)
+		}
+		fmt.Fprint(bw, "0 ")
+		for _, h := range line.Hints {
+			fmt.Fprintf(bw, "%d ", h)
+		}
+		fmt.Fprintln(bw, "0")
+	}
+	return bw.Flush()
+}
+
+// Read reads the lines of a proof. Deletion lines (id d ids 0) are skipped, lines starting with c are comments.
+func Read(r io.Reader) ([]Line, error) {
+	lines := []Line{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "d" {
+			continue
+		}
+		numbers := make([]int, len(fields))
+		for i, field := range fields {
+			v, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q", n, field)
+			}
+			numbers[i] = v
+		}
+		line := Line{ID: numbers[0], Clause: []clause.Literal{}, Hints: []int{}}
+		rest := numbers[1:]
+		k := 0
+		for ; k < len(rest) && rest[k] != 0; k++ {
+			line.Clause = append(line.Clause, clause.Literal(rest[k]))
+		}
+		if k == len(rest) {
+			return nil, fmt.Errorf("line %d: clause is not terminated by 0", n)
+		}
+		for k++; k < len(rest) && rest[k] != 0; k++ {
+			line.Hints = append(line.Hints, rest[k])
+		}
+		if k != len(rest)-1 {
+			return nil, fmt.Errorf("line %d: hints are not terminated by 0", n)
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// Check verifies an LRAT proof of unsatisfiability of the clause set. For every line, assigning
+// the literals of its clause false and propagating the hints in order must make each hint but the
+// last unit and falsify the last one. The proof must add the empty clause.
+// Returns an error describing the first invalid line.
+func Check(set []clause.Clause, lines []Line) error {
+	clauses := map[int][]clause.Literal{}
+	for i := range set {
+		clauses[i+1] = set[i].Literals()
+	}
+	last := len(set)
+	for _, line := range lines {
+		if line.ID <= last {
+			return fmt.Errorf("clause %d: number is not larger than %d", line.ID, last)
+		}
+		if err := check(clauses, line); err != nil {
+			return fmt.Errorf("clause %d: %v", line.ID, err)
+		}
+		if len(line.Clause) == 0 {
+			return nil
+		}
+		clauses[line.ID] = line.Clause
+		last = line.ID
+	}
+	return fmt.Errorf("the proof does not add the empty clause")
+}
+
+// check verifies the hints of a single line.
+func check(clauses map[int][]clause.Literal, line Line) error {
+	assignment := map[clause.Literal]bool{}
+	for _, l := range line.Clause {
+		assignment[-l] = true
+	}
+	for _, h := range line.Hints {
+		literals, ok := clauses[h]
+		if !ok {
+			return fmt.Errorf("hint %d is not a known clause", h)
+		}
+		unassigned := []clause.Literal{}
+		for _, l := range literals {
+			if assignment[l] {
+				return fmt.Errorf("hint %d is satisfied", h)
+			}
+			if !assignment[-l] {
+				unassigned = append(unassigned, l)
+			}
+		}
+		switch len(unassigned) {
+		case 0:
+			return nil
+		case 1:
+			assignment[unassigned[0]] = true
+		default:
+			return fmt.Errorf("hint %d is not unit", h)
+		}
+	}
+	return fmt.Errorf("the hints do not lead to a conflict")
+}
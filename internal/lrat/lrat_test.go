@@ -0,0 +1,106 @@
+package lrat
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+)
+
+func TestWrite(t *testing.T) {
+	set := clausetest.ParseSet(t, "A,B", "-A,B", "-B")
+	unsat, steps := clause.Refute(set, nil)
+	if !unsat {
+		t.Fatalf("Refute() = false; want true")
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, FromRefutation(set, steps)); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if expected := "4 2 0 1 2 0\n5 0 3 4 0\n"; buf.String() != expected {
+		t.Errorf("Write() = %q; want %q", buf.String(), expected)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	// Refutations of random clause sets survive writing, reading and checking
+	r := rand.New(rand.NewSource(5))
+	replayed := 0
+	for n := 0; n < 200; n++ {
+		set := clausetest.RandomSet(r, 1+r.Intn(4), 2+r.Intn(7))
+		unsat, steps := clause.Refute(set, nil)
+		if !unsat {
+			continue
+		}
+		replayed++
+		var buf bytes.Buffer
+		if err := Write(&buf, FromRefutation(set, steps)); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+		lines, err := Read(&buf)
+		if err != nil {
+			t.Fatalf("Read() failed: %v", err)
+		}
+		if err := Check(set, lines); err != nil {
+			t.Errorf("Check() = %v for clauses %v", err, set)
+		}
+	}
+	if replayed == 0 {
+		t.Fatalf("no unsatisfiable clause sets generated")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	set := clausetest.ParseSet(t, "A,B", "-A,B", "A,-B", "-A,-B")
+	tests := []struct {
+		name     string
+		proof    string
+		expected string
+	}{
+		{"valid", "5 2 0 1 2 0\n6 -2 0 3 4 0\n7 0 5 6 0\n", ""},
+		{"comments and deletions", "c proof\n5 2 0 1 2 0\n5 d 1 2 0\n6 -2 0 3 4 0\n7 0 5 6 0\n", ""},
+		{"number too small", "4 2 0 1 2 0\n", "clause 4: number is not larger than 4"},
+		{"unknown hint", "5 2 0 1 9 0\n", "clause 5: hint 9 is not a known clause"},
+		{"satisfied hint", "5 -1 0 1 0\n", "clause 5: hint 1 is satisfied"},
+		{"not unit", "5 0 1 0\n", "clause 5: hint 1 is not unit"},
+		{"no conflict", "5 2 0 1 0\n", "clause 5: the hints do not lead to a conflict"},
+		{"no empty clause", "5 2 0 1 2 0\n", "the proof does not add the empty clause"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := Read(strings.NewReader(tt.proof))
+			if err != nil {
+				t.Fatalf("Read() failed: %v", err)
+			}
+			err = Check(set, lines)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Check() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Check() = %v; want %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, input := range []string{"5 x 0 0\n", "5 1 2\n", "5 1 0 2\n"} {
+		if _, err := Read(strings.NewReader(input)); err == nil {
+			t.Errorf("Read(%q) succeeded; want error", input)
+		}
+	}
+}
+
+func TestEmptyInputClause(t *testing.T) {
+	set := []clause.Clause{*clause.New()}
+	lines := FromRefutation(set, []clause.Step{})
+	if err := Check(set, lines); err != nil {
+		t.Errorf("Check() = %v; want nil", err)
+	}
+}
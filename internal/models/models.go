@@ -0,0 +1,64 @@
+// Package models enumerates the satisfying assignments of a set of clauses.
+package models
+
+import (
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/dpll"
+)
+
+// Enumerate calls fn for every satisfying assignment of the clause set as soon as it is found.
+// Each model is given as the true literals of its variables.
+// Enumeration stops early if fn returns false.
+//
+// If project is not empty, models are projected onto the given variables (in the given order)
+// and every projected assignment is reported once. Otherwise all variables of the set are used.
+// If limit is greater than zero, at most limit models are reported.
+//
+// Returns the number of reported models.
+//
+// After each model a blocking clause, the negation of the (projected) model,
+// is added to the set, so the next search finds a different assignment.
+func Enumerate(set []clause.Clause, project []clause.Literal, limit int, fn func(model []clause.Literal) bool) int {
+	vars := project
+	if len(vars) == 0 {
+		vars = clause.Variables(set)
+	}
+	current := make([]clause.Clause, len(set), len(set)+1)
+	copy(current, set)
+	count := 0
+	for limit <= 0 || count < limit {
+		unsat, model := dpll.Solve(current, nil, nil)
+		if unsat {
+			break
+		}
+		projected := restrict(model, vars)
+		count++
+		if !fn(projected) {
+			break
+		}
+		blocking := clause.New()
+		for _, l := range projected {
+			blocking.Insert(-l)
+		}
+		current = append(current, *blocking)
+	}
+	return count
+}
+
+// restrict returns the literals of the model on the given variables.
+// Variables the model does not assign are free and taken as false.
+func restrict(model []clause.Literal, vars []clause.Literal) []clause.Literal {
+	value := map[clause.Literal]bool{}
+	for _, l := range model {
+		value[l.Var()] = l > 0
+	}
+	result := make([]clause.Literal, len(vars))
+	for i, v := range vars {
+		if value[v] {
+			result[i] = v
+		} else {
+			result[i] = -v
+		}
+	}
+	return result
+}
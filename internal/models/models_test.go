@@ -0,0 +1,120 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+)
+
+func TestEnumerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		project  []clause.Literal
+		limit    int
+		expected []string
+	}{
+		{
+			name:     "unsatisfiable",
+			clauses:  []string{"A", "-A"},
+			expected: []string{},
+		},
+		{
+			name:     "empty clause set",
+			clauses:  []string{},
+			expected: []string{"{}"},
+		},
+		{
+			name:     "single unit",
+			clauses:  []string{"-A"},
+			expected: []string{"{-A}"},
+		},
+		{
+			name:     "disjunction",
+			clauses:  []string{"A,B"},
+			expected: []string{"{A, B}", "{A, -B}", "{-A, B}"},
+		},
+		{
+			name:     "limit",
+			clauses:  []string{"A,B"},
+			limit:    2,
+			expected: []string{"{A, B}", "{A, -B}"},
+		},
+		{
+			name:     "projection",
+			clauses:  []string{"A,B", "-A,C"},
+			project:  []clause.Literal{1},
+			expected: []string{"{A}", "{-A}"},
+		},
+		{
+			name:     "projection onto free variable",
+			clauses:  []string{"A"},
+			project:  []clause.Literal{1, 2},
+			expected: []string{"{A, -B}", "{A, B}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := clausetest.ParseSet(t, tt.clauses...)
+			result := []string{}
+			count := Enumerate(set, tt.project, tt.limit, func(model []clause.Literal) bool {
+				result = append(result, formatModel(model))
+				return true
+			})
+			if count != len(result) {
+				t.Errorf("Enumerate() = %d; reported %d models", count, len(result))
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Enumerate(%v) = %v; want %v", tt.clauses, result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("Enumerate(%v) = %v; want %v", tt.clauses, result, tt.expected)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestEnumerateStop(t *testing.T) {
+	set := clausetest.ParseSet(t, "A,B,C")
+	calls := 0
+	count := Enumerate(set, nil, 0, func(model []clause.Literal) bool {
+		calls++
+		return false
+	})
+	if calls != 1 || count != 1 {
+		t.Errorf("Enumerate() called fn %d times and returned %d; want 1 and 1", calls, count)
+	}
+}
+
+func TestEnumerateAllModels(t *testing.T) {
+	// (A ∨ B ∨ C) has 7 of 8 assignments as models, all distinct and all satisfying
+	set := clausetest.ParseSet(t, "A,B,C")
+	seen := map[string]bool{}
+	Enumerate(set, nil, 0, func(model []clause.Literal) bool {
+		key := formatModel(model)
+		if seen[key] {
+			t.Errorf("model %s reported twice", key)
+		}
+		seen[key] = true
+		if model[0] < 0 && model[1] < 0 && model[2] < 0 {
+			t.Errorf("model %s does not satisfy the clause set", key)
+		}
+		return true
+	})
+	if len(seen) != 7 {
+		t.Errorf("Enumerate() found %d models; want 7", len(seen))
+	}
+}
+
+// Helper function to format a model in set notation
+func formatModel(model []clause.Literal) string {
+	c := clause.New()
+	for _, l := range model {
+		c.Insert(l)
+	}
+	return c.String()
+}
@@ -0,0 +1,489 @@
+// Package preprocess simplifies a clause set before it is decided.
+//
+// The stages of the pipeline either derive clauses that follow from the set or remove clauses
+// without changing whether the set is satisfiable. Removing a clause may lose models, so every
+// removed clause that is not implied by the rest is recorded with a witness literal. A model of
+// the simplified set is turned into a model of the original set by going through the recorded
+// clauses backwards and making the witness true for every clause the model falsifies.
+package preprocess
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/drat"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+// Names of the stages.
+const (
+	StageUnits           = "units"
+	StageProbing         = "probe"
+	StageEquivalence     = "equivalence"
+	StagePure            = "pure"
+	StageSubsumption     = "subsume"
+	StageSelfSubsumption = "strengthen"
+	StageElimination     = "eliminate"
+	StageBlocked         = "blocked"
+)
+
+// Stages lists the names of the stages in the order they run.
+var Stages = []string{StageUnits, StageProbing, StageEquivalence, StagePure, StageSubsumption, StageSelfSubsumption, StageElimination, StageBlocked}
+
+// Options selects the stages of the pipeline.
+type Options struct {
+	// Units assigns the literal of every unit clause, removing the clauses it satisfies
+	// and its negation from every other clause.
+	Units bool
+	// Probing assigns every literal in turn and propagates it. The negation of a literal that
+	// leads to a conflict is added as a unit, as is every literal implied by both a literal and its negation.
+	Probing bool
+	// Equivalence replaces literals that imply each other through binary clauses by one representative.
+	Equivalence bool
+	// Pure removes the clauses containing a literal whose negation occurs nowhere.
+	Pure bool
+	// Subsumption removes every clause that contains all literals of another clause.
+	Subsumption bool
+	// SelfSubsumption removes -l from a clause D if a clause C contains l and, apart from l, only literals of D.
+	SelfSubsumption bool
+	// Elimination replaces the clauses on a variable by their resolvents if there are not more resolvents than clauses.
+	Elimination bool
+	// Blocked removes a clause containing a literal l if its resolvents with every clause containing -l are tautologies.
+	Blocked bool
+}
+
+// ParseStages parses a comma-separated list of stage names. "all" selects every stage.
+func ParseStages(s string) (Options, error) {
+	opts := Options{}
+	for _, name := range strings.Split(s, ",") {
+		switch strings.TrimSpace(name) {
+		case "all":
+			opts = Options{true, true, true, true, true, true, true, true}
+		case StageUnits:
+			opts.Units = true
+		case StageProbing:
+			opts.Probing = true
+		case StageEquivalence:
+			opts.Equivalence = true
+		case StagePure:
+			opts.Pure = true
+		case StageSubsumption:
+			opts.Subsumption = true
+		case StageSelfSubsumption:
+			opts.SelfSubsumption = true
+		case StageElimination:
+			opts.Elimination = true
+		case StageBlocked:
+			opts.Blocked = true
+		default:
+			return Options{}, fmt.Errorf("unknown stage: %q (stages: all, %s)", name, strings.Join(Stages, ", "))
+		}
+	}
+	return opts, nil
+}
+
+// Reconstruction turns models of a simplified clause set into models of the original set.
+type Reconstruction struct {
+	// vars are the variables of the original set
+	vars []clause.Literal
+	// removed lists the removed clauses with their witnesses in the order they were removed
+	removed []witnessed
+}
+
+// witnessed is a removed clause together with the literal that satisfies it when made true.
+type witnessed struct {
+	clause  clause.Clause
+	witness clause.Literal
+}
+
+// Model returns a model of the original set for a model of the simplified set, given as literals
+// that are true. Variables the model does not list are taken to be false. The result assigns every
+// variable of the original set and of the model, sorted by variable.
+func (r *Reconstruction) Model(model []clause.Literal) []clause.Literal {
+	value := map[clause.Literal]bool{}
+	vars := append([]clause.Literal{}, r.vars...)
+	for _, l := range model {
+		if _, ok := value[l.Var()]; !ok && !contains(vars, l.Var()) {
+			vars = append(vars, l.Var())
+		}
+		value[l.Var()] = l > 0
+	}
+	for i := len(r.removed) - 1; i >= 0; i-- {
+		e := r.removed[i]
+		if !satisfied(e.clause, value) {
+			value[e.witness.Var()] = e.witness > 0
+		}
+	}
+	sort.Slice(vars, func(i, k int) bool { return vars[i] < vars[k] })
+	result := make([]clause.Literal, len(vars))
+	for i, v := range vars {
+		if value[v] {
+			result[i] = v
+		} else {
+			result[i] = -v
+		}
+	}
+	return result
+}
+
+// pipeline holds the clause set while it is simplified.
+type pipeline struct {
+	clauses []clause.Clause
+	st      *stats.Stats
+	proof   *drat.Proof
+	rec     *Reconstruction
+}
+
+// Run simplifies a clause set with the selected stages, repeating them until none changes the set.
+// If the empty clause is derived, it stops and returns only the empty clause. The set itself is not modified.
+//
+// If st is not nil, removed subsumed clauses are recorded as subsumptions.
+// If proof is not nil, the simplification is recorded as DRAT proof steps: derived clauses are
+// added before the clauses they replace are deleted. A proof of the simplified set appended to it
+// proves the original set unsatisfiable.
+func Run(set []clause.Clause, opts Options, st *stats.Stats, proof *drat.Proof) ([]clause.Clause, *Reconstruction) {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	p := &pipeline{st: st, proof: proof, rec: &Reconstruction{vars: clause.Variables(set)}}
+	for i := range set {
+		if clause.Index(p.clauses, set[i]) >= 0 {
+			p.proof.Delete(set[i])
+			continue
+		}
+		p.clauses = append(p.clauses, *set[i].Copy())
+	}
+	stages := []struct {
+		enabled bool
+		run     func() bool
+	}{
+		{opts.Units, p.units},
+		{opts.Probing, p.probe},
+		{opts.Equivalence, p.substitute},
+		{opts.Pure, p.pure},
+		{opts.Subsumption, p.subsume},
+		{opts.SelfSubsumption, p.strengthen},
+		{opts.Elimination, p.eliminate},
+		{opts.Blocked, p.blocked},
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, stage := range stages {
+			if p.unsat() {
+				return []clause.Clause{*clause.New()}, p.rec
+			}
+			if stage.enabled && stage.run() {
+				changed = true
+			}
+		}
+	}
+	if p.unsat() {
+		return []clause.Clause{*clause.New()}, p.rec
+	}
+	return p.clauses, p.rec
+}
+
+// unsat checks if the set contains the empty clause.
+func (p *pipeline) unsat() bool {
+	return clause.Index(p.clauses, *clause.New()) >= 0
+}
+
+// add adds a derived clause unless the set already contains it.
+func (p *pipeline) add(c clause.Clause) {
+	if clause.Index(p.clauses, c) < 0 {
+		p.proof.Add(c)
+		p.clauses = append(p.clauses, c)
+	}
+}
+
+// remove removes the clauses for which drop returns true and records those with a witness
+// other than ErrorLiteral for the reconstruction.
+func (p *pipeline) remove(drop func(c clause.Clause) (bool, clause.Literal)) bool {
+	kept := []clause.Clause{}
+	for _, c := range p.clauses {
+		ok, witness := drop(c)
+		if !ok {
+			kept = append(kept, c)
+			continue
+		}
+		p.proof.Delete(c)
+		if witness != clause.ErrorLiteral {
+			p.rec.removed = append(p.rec.removed, witnessed{c, witness})
+		}
+	}
+	changed := len(kept) < len(p.clauses)
+	p.clauses = kept
+	return changed
+}
+
+// units assigns the literals of unit clauses until there are none left.
+func (p *pipeline) units() bool {
+	changed := false
+	for !p.unsat() {
+		i := -1
+		for k := range p.clauses {
+			if p.clauses[k].Size() == 1 {
+				i = k
+				break
+			}
+		}
+		if i < 0 {
+			break
+		}
+		l := p.clauses[i].Literals()[0]
+		// Strengthen first, so the unit is present when the shorter clauses are added
+		for k, c := range p.clauses {
+			if c.Contains(-l) {
+				p.replace(k, without(c, -l))
+			}
+		}
+		p.remove(func(c clause.Clause) (bool, clause.Literal) {
+			if c.Equals(unit(l)) {
+				return true, l
+			}
+			return c.Contains(l), clause.ErrorLiteral
+		})
+		changed = true
+	}
+	return changed
+}
+
+// replace replaces clause i by a clause implied by the set.
+func (p *pipeline) replace(i int, c clause.Clause) {
+	p.proof.Add(c)
+	p.proof.Delete(p.clauses[i])
+	p.clauses[i] = c
+}
+
+// pure removes the clauses containing pure literals.
+func (p *pipeline) pure() bool {
+	changed := false
+	for {
+		occurs := map[clause.Literal]bool{}
+		for _, c := range p.clauses {
+			for _, l := range c.Literals() {
+				occurs[l] = true
+			}
+		}
+		pure := clause.ErrorLiteral
+		for _, v := range clause.Variables(p.clauses) {
+			if occurs[v] != occurs[-v] {
+				pure = v
+				if occurs[-v] {
+					pure = -v
+				}
+				break
+			}
+		}
+		if pure == clause.ErrorLiteral {
+			return changed
+		}
+		p.remove(func(c clause.Clause) (bool, clause.Literal) {
+			if c.Contains(pure) {
+				return true, pure
+			}
+			return false, clause.ErrorLiteral
+		})
+		changed = true
+	}
+}
+
+// subsume removes every clause subsumed by another clause. Of two equal clauses, the later one is removed.
+func (p *pipeline) subsume() bool {
+	kept := []clause.Clause{}
+	changed := false
+	for i, c := range p.clauses {
+		subsumed := false
+		for k := range p.clauses {
+			if k == i || p.clauses[k].Size() > c.Size() || !subset(p.clauses[k], c) {
+				continue
+			}
+			// Of two equal clauses only the later one is subsumed
+			if p.clauses[k].Size() < c.Size() || k < i {
+				subsumed = true
+				break
+			}
+		}
+		if subsumed {
+			p.proof.Delete(c)
+			p.st.Subsumptions++
+			changed = true
+		} else {
+			kept = append(kept, c)
+		}
+	}
+	p.clauses = kept
+	return changed
+}
+
+// strengthen applies self-subsuming resolution until no clause can be strengthened.
+func (p *pipeline) strengthen() bool {
+	changed := false
+	for again := true; again && !p.unsat(); {
+		again = false
+		for i := range p.clauses {
+			for k := range p.clauses {
+				if i == k {
+					continue
+				}
+				l, ok := p.clauses[i].Pivot(p.clauses[k])
+				if !ok || !subset(without(p.clauses[i], l), p.clauses[k]) {
+					continue
+				}
+				p.replace(k, without(p.clauses[k], -l))
+				again = true
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// eliminate eliminates variables, fewest occurrences first, as long as the number of clauses does not grow.
+func (p *pipeline) eliminate() bool {
+	changed := false
+	for _, v := range p.byOccurrences() {
+		if p.unsat() {
+			break
+		}
+		var pos, neg []clause.Clause
+		for _, c := range p.clauses {
+			if c.Contains(v) {
+				pos = append(pos, c)
+			} else if c.Contains(-v) {
+				neg = append(neg, c)
+			}
+		}
+		if len(pos)+len(neg) == 0 {
+			continue
+		}
+		resolvents := []clause.Clause{}
+		for i := range pos {
+			for k := range neg {
+				r, ok := pos[i].Resolve(neg[k])
+				if ok && r != nil && clause.Index(resolvents, *r) < 0 {
+					resolvents = append(resolvents, *r)
+				}
+			}
+		}
+		if len(resolvents) > len(pos)+len(neg) {
+			continue
+		}
+		for _, r := range resolvents {
+			p.add(r)
+		}
+		p.remove(func(c clause.Clause) (bool, clause.Literal) {
+			switch {
+			case c.Contains(v):
+				return true, v
+			case c.Contains(-v):
+				return true, -v
+			}
+			return false, clause.ErrorLiteral
+		})
+		changed = true
+	}
+	return changed
+}
+
+// byOccurrences returns the variables of the set, those occurring in the fewest clauses first.
+func (p *pipeline) byOccurrences() []clause.Literal {
+	count := map[clause.Literal]int{}
+	for _, c := range p.clauses {
+		for _, l := range c.Literals() {
+			count[l.Var()]++
+		}
+	}
+	vars := clause.Variables(p.clauses)
+	sort.SliceStable(vars, func(i, k int) bool { return count[vars[i]] < count[vars[k]] })
+	return vars
+}
+
+// blocked removes blocked clauses until none is left.
+func (p *pipeline) blocked() bool {
+	changed := false
+	for again := true; again; {
+		again = false
+		for i := range p.clauses {
+			if l, ok := p.blocking(i); ok {
+				c := p.clauses[i]
+				p.remove(func(d clause.Clause) (bool, clause.Literal) {
+					if d.Equals(c) {
+						return true, l
+					}
+					return false, clause.ErrorLiteral
+				})
+				again = true
+				changed = true
+				break
+			}
+		}
+	}
+	return changed
+}
+
+// blocking returns a literal on which clause i is blocked: every resolvent of the clause
+// with a clause containing the negation of the literal is a tautology.
+func (p *pipeline) blocking(i int) (clause.Literal, bool) {
+	c := p.clauses[i]
+	for _, l := range c.Literals() {
+		blocked := true
+		for k := range p.clauses {
+			if k == i || !p.clauses[k].Contains(-l) {
+				continue
+			}
+			// Resolve fails if the clauses clash on a second literal, so the resolvent is a tautology
+			if r, _ := c.Resolve(p.clauses[k]); r != nil {
+				blocked = false
+				break
+			}
+		}
+		if blocked {
+			return l, true
+		}
+	}
+	return clause.ErrorLiteral, false
+}
+
+// without returns a copy of the clause without the literal l.
+func without(c clause.Clause, l clause.Literal) clause.Clause {
+	result := clause.New()
+	for _, k := range c.Literals() {
+		if k != l {
+			result.Insert(k)
+		}
+	}
+	return *result
+}
+
+// subset checks if every literal of a is in b.
+func subset(a, b clause.Clause) bool {
+	for _, l := range a.Literals() {
+		if !b.Contains(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// satisfied checks if a literal of the clause is true. Unassigned variables are false.
+func satisfied(c clause.Clause, value map[clause.Literal]bool) bool {
+	for _, l := range c.Literals() {
+		if value[l.Var()] == (l > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains checks if the literal is in the list.
+func contains(literals []clause.Literal, l clause.Literal) bool {
+	for _, k := range literals {
+		if k == l {
+			return true
+		}
+	}
+	return false
+}
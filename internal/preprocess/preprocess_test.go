@@ -0,0 +1,137 @@
+package preprocess
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+	"github.com/thxrsxm/res/internal/dp"
+	"github.com/thxrsxm/res/internal/drat"
+	"github.com/thxrsxm/res/internal/gen"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+var all = Options{true, true, true, true, true, true, true, true}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		opts     Options
+		expected []string
+	}{
+		{"no stages", []string{"A,B", "-A"}, Options{}, []string{"A,B", "-A"}},
+		{"duplicates", []string{"A,B", "B,A"}, Options{}, []string{"A,B"}},
+		{"units", []string{"A", "-A,B", "B,C,D", "-B,-C,D"}, Options{Units: true}, []string{"-C,D"}},
+		{"units conflict", []string{"A", "-A,B", "-B"}, Options{Units: true}, []string{""}},
+		{"failed literal", []string{"-A,B", "-A,-B", "A,C,D"}, Options{Probing: true}, []string{"-A,B", "-A,-B", "A,C,D", "-A"}},
+		{"implied unit", []string{"A,B", "-A,B", "C,D"}, Options{Probing: true}, []string{"A,B", "-A,B", "C,D", "B"}},
+		{"equivalence", []string{"-A,B", "A,-B", "A,C", "-B,D"}, Options{Equivalence: true}, []string{"A,C", "-A,D"}},
+		{"equivalent to negation", []string{"A,B", "-A,-B", "-A,B", "A,-B"}, Options{Equivalence: true}, []string{""}},
+		{"pure", []string{"A,B", "A,-B", "-B,C"}, Options{Pure: true}, []string{}},
+		{"subsumption", []string{"A", "A,B", "A,B,C", "-A,C"}, Options{Subsumption: true}, []string{"A", "-A,C"}},
+		{"self-subsumption", []string{"A,B", "-A,B,C", "-B,C"}, Options{SelfSubsumption: true}, []string{"A,B", "B,C", "C"}},
+		{"elimination", []string{"A,B", "-A,C", "-B,-C"}, Options{Elimination: true}, []string{}},
+		{"blocked", []string{"A,B", "-A,-B", "A,C", "-C,D", "-D,-A"}, Options{Blocked: true}, []string{"A,C", "-C,D", "-D,-A"}},
+		{"all", []string{"A,B", "-A,B", "A,-B", "-A,-B"}, all, []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := Run(clausetest.ParseSet(t, tt.clauses...), tt.opts, nil, nil)
+			expected := clausetest.ParseSet(t, tt.expected...)
+			if len(result) != len(expected) {
+				t.Fatalf("Run(%v) = %v; want %v", tt.clauses, result, expected)
+			}
+			for i := range expected {
+				if clause.Index(result, expected[i]) < 0 {
+					t.Errorf("Run(%v) = %v; want %v", tt.clauses, result, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestRunStats(t *testing.T) {
+	st := &stats.Stats{}
+	Run(clausetest.ParseSet(t, "A", "A,B", "A,C"), Options{Subsumption: true}, st, nil)
+	if st.Subsumptions != 2 {
+		t.Errorf("Subsumptions = %d; want 2", st.Subsumptions)
+	}
+}
+
+func TestRunRandom(t *testing.T) {
+	stages := []Options{{Units: true}, {Probing: true}, {Equivalence: true}, {Pure: true}, {Subsumption: true}, {SelfSubsumption: true}, {Elimination: true}, {Blocked: true}, all}
+	for seed := int64(0); seed < 40; seed++ {
+		// Every other set is 2-CNF, so it has binary implications for equivalences
+		set, err := gen.Random(2+int(seed%2), 5, 5+int(seed%15), seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unsat, _ := clause.BruteForce(set)
+		for _, opts := range stages {
+			proof := &drat.Proof{}
+			simplified, rec := Run(set, opts, nil, proof)
+			result, model := clause.BruteForce(simplified)
+			if result != unsat {
+				t.Fatalf("seed %d, %+v: simplified set %v unsatisfiable = %v; want %v", seed, opts, simplified, result, unsat)
+			}
+			if unsat {
+				dp.Solve(simplified, nil, proof)
+				if err := drat.Check(set, proof); err != nil {
+					t.Errorf("seed %d, %+v: proof rejected: %v", seed, opts, err)
+				}
+				continue
+			}
+			model = rec.Model(model)
+			if len(model) != len(clause.Variables(set)) {
+				t.Errorf("seed %d, %+v: model %v does not assign every variable of %v", seed, opts, model, set)
+			}
+			if !clausetest.Satisfies(set, model) {
+				t.Errorf("seed %d, %+v: reconstructed model %v does not satisfy %v", seed, opts, model, set)
+			}
+		}
+	}
+}
+
+func TestModel(t *testing.T) {
+	set := clausetest.ParseSet(t, "A", "-A,B", "B,C", "-C,D")
+	simplified, rec := Run(set, all, nil, nil)
+	if len(simplified) != 0 {
+		t.Fatalf("Run(%v) = %v; want no clauses", set, simplified)
+	}
+	model := rec.Model(nil)
+	if !clausetest.Satisfies(set, model) {
+		t.Errorf("Model(nil) = %v does not satisfy %v", model, set)
+	}
+}
+
+func TestModelEquivalence(t *testing.T) {
+	set := clausetest.ParseSet(t, "-A,-B", "A,B", "B,C", "-C,-A,D")
+	simplified, rec := Run(set, Options{Equivalence: true}, nil, nil)
+	// B is equivalent to -A
+	expected := clausetest.ParseSet(t, "-A,C", "-C,-A,D")
+	if len(simplified) != 2 || clause.Index(simplified, expected[0]) < 0 || clause.Index(simplified, expected[1]) < 0 {
+		t.Fatalf("Run(%v) = %v; want %v", set, simplified, expected)
+	}
+	model := rec.Model([]clause.Literal{-1, 3, 4})
+	if !reflect.DeepEqual(model, []clause.Literal{-1, 2, 3, 4}) {
+		t.Errorf("Model([-1 3 4]) = %v; want [-1 2 3 4]", model)
+	}
+}
+
+func TestParseStages(t *testing.T) {
+	opts, err := ParseStages("units, eliminate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts != (Options{Units: true, Elimination: true}) {
+		t.Errorf("ParseStages(%q) = %+v", "units, eliminate", opts)
+	}
+	if opts, err := ParseStages("all"); err != nil || opts != all {
+		t.Errorf("ParseStages(%q) = %+v, %v; want every stage", "all", opts, err)
+	}
+	if _, err := ParseStages("units,magic"); err == nil {
+		t.Errorf("ParseStages accepted an unknown stage")
+	}
+}
@@ -0,0 +1,169 @@
+package preprocess
+
+import (
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/trail"
+	"github.com/thxrsxm/res/internal/twosat"
+)
+
+// probe assigns every literal of the set in turn and propagates it. A literal whose propagation
+// falsifies a clause is failed, so its negation is added as a unit. A literal propagated by both
+// a literal and its negation is added as a unit as well.
+func (p *pipeline) probe() bool {
+	changed := false
+	for _, v := range clause.Variables(p.clauses) {
+		if p.unsat() {
+			break
+		}
+		pos, ok := p.implied(v)
+		if !ok {
+			changed = p.assert(-v) || changed
+			continue
+		}
+		neg, ok := p.implied(-v)
+		if !ok {
+			changed = p.assert(v) || changed
+			continue
+		}
+		for _, l := range pos {
+			if !contains(neg, l) || clause.Index(p.clauses, unit(l)) >= 0 {
+				continue
+			}
+			// Both v and -v imply l, so the clauses -v,l and v,l follow by propagation, and l from them
+			a, b := unit(-v), unit(v)
+			a.Insert(l)
+			b.Insert(l)
+			p.proof.Add(a)
+			p.proof.Add(b)
+			p.add(unit(l))
+			p.proof.Delete(a)
+			p.proof.Delete(b)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// assert adds the unit clause of the literal unless the set contains it already.
+func (p *pipeline) assert(l clause.Literal) bool {
+	if clause.Index(p.clauses, unit(l)) >= 0 {
+		return false
+	}
+	p.add(unit(l))
+	return true
+}
+
+// unit returns the unit clause of the literal.
+func unit(l clause.Literal) clause.Clause {
+	c := clause.New()
+	c.Insert(l)
+	return *c
+}
+
+// implied assigns the literal and propagates unit clauses. It returns the literals assigned
+// besides l, or false if a clause is falsified.
+func (p *pipeline) implied(l clause.Literal) ([]clause.Literal, bool) {
+	clauses := make([][]clause.Literal, len(p.clauses))
+	for i := range p.clauses {
+		clauses[i] = p.clauses[i].Literals()
+	}
+	t := trail.New()
+	t.Assign(l)
+	if t.Propagate(clauses, nil) >= 0 {
+		return nil, false
+	}
+	return t.Literals()[1:], true
+}
+
+// substitute finds the literals that imply each other through binary clauses, the strongly
+// connected components of the implication graph, and replaces every literal by the representative
+// of its component, the literal with the smallest variable. The replaced variables are recorded
+// for the reconstruction as equal to their representatives.
+func (p *pipeline) substitute() bool {
+	rep := map[clause.Literal]clause.Literal{}
+	for _, component := range p.components() {
+		r := component[0]
+		for _, l := range component {
+			if l.Var() < r.Var() {
+				r = l
+			}
+		}
+		for _, l := range component {
+			if l == -r {
+				// l implies -l and -l implies l
+				p.assert(r)
+				p.assert(-r)
+				p.add(*clause.New())
+				return true
+			}
+			rep[l] = r
+		}
+	}
+	replaced := []clause.Literal{}
+	for _, v := range clause.Variables(p.clauses) {
+		if r, ok := rep[v]; ok && r.Var() != v {
+			replaced = append(replaced, v)
+		}
+	}
+	if len(replaced) == 0 {
+		return false
+	}
+	// The substituted clauses follow by propagation while the binary clauses are present
+	for _, c := range p.clauses {
+		if d, ok := substituted(c, rep); ok && !d.Equals(c) {
+			p.add(d)
+		}
+	}
+	p.remove(func(c clause.Clause) (bool, clause.Literal) {
+		for _, v := range replaced {
+			if c.Contains(v) || c.Contains(-v) {
+				return true, clause.ErrorLiteral
+			}
+		}
+		return false, clause.ErrorLiteral
+	})
+	for _, v := range replaced {
+		// v equals its representative r: v,-r is made true by v and -v,r by -v
+		r := rep[v]
+		a, b := unit(v), unit(-v)
+		a.Insert(-r)
+		b.Insert(r)
+		p.rec.removed = append(p.rec.removed, witnessed{a, v}, witnessed{b, -v})
+	}
+	return true
+}
+
+// substituted returns the clause with every literal replaced by its representative,
+// or false if the result is a tautology.
+func substituted(c clause.Clause, rep map[clause.Literal]clause.Literal) (clause.Clause, bool) {
+	result := clause.New()
+	for _, l := range c.Literals() {
+		r, ok := rep[l]
+		if !ok {
+			r = l
+		}
+		if result.Contains(-r) {
+			return clause.Clause{}, false
+		}
+		result.Insert(r)
+	}
+	return *result, true
+}
+
+// components returns the strongly connected components with more than one literal of the
+// implication graph of the binary and unit clauses, in the order twosat numbers them.
+func (p *pipeline) components() [][]clause.Literal {
+	index := twosat.Components(p.clauses)
+	literals := map[int][]clause.Literal{}
+	for _, v := range clause.Variables(p.clauses) {
+		literals[index[v]] = append(literals[index[v]], v)
+		literals[index[-v]] = append(literals[index[-v]], -v)
+	}
+	components := [][]clause.Literal{}
+	for i := 0; i < len(literals); i++ {
+		if len(literals[i]) > 1 {
+			components = append(components, literals[i])
+		}
+	}
+	return components
+}
@@ -0,0 +1,249 @@
+// Package primes compiles a clause set into its prime implicates and a DNF into its prime implicants.
+//
+// A prime implicate of a clause set is a clause that follows from the set and no proper subset
+// of which follows from it. Resolution saturation derives every implicate or a clause subsuming
+// it, so saturating while removing subsumed clauses leaves exactly the prime implicates.
+//
+// By duality, a term is a prime implicant of a DNF if its negation is a prime implicate of the
+// negated DNF, the clause set of the negated terms.
+//
+// Saturation only knows which clauses are left once it ends. To report primes earlier, the
+// streaming variants check every clause as it is kept: an implicate C is prime if, for every
+// literal l of C, the set together with the negation of C without l is satisfiable. A prime is
+// never subsumed later, so it is reported exactly once.
+package primes
+
+import (
+	"context"
+	"sort"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/incremental"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+// Implicates passes the prime implicates of the clause set to fn, shortest first and clauses of the
+// same size in the order of their literals. Only clauses of at most maxSize literals are passed;
+// 0 means no limit. Enumeration stops early if fn returns false. Returns the number of clauses passed.
+//
+// The primes are sorted, so the first one is passed after the saturation. The size limit only
+// filters the primes passed, as longer resolvents may still resolve to short primes.
+// If st is not nil, resolvents, tautologies and subsumed clauses are recorded.
+func Implicates(set []clause.Clause, maxSize int, st *stats.Stats, fn func(c clause.Clause) bool) int {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	return emit(saturate(set, st, nil), maxSize, fn)
+}
+
+// StreamImplicates works like Implicates, but passes every prime implicate as soon as the
+// saturation derives it, in the order they are derived. Every clause of at most maxSize literals
+// the saturation keeps costs one query of an incremental solver per literal; the queries are not
+// recorded in st. The saturation stops as soon as fn returns false.
+func StreamImplicates(set []clause.Clause, maxSize int, st *stats.Stats, fn func(c clause.Clause) bool) int {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	return stream(set, maxSize, st, fn)
+}
+
+// Implicants passes the prime implicants of the DNF whose terms are given as clauses to fn, in the
+// order and with the limits of Implicates. Every implicant is passed as the clause of its literals.
+func Implicants(terms []clause.Clause, maxSize int, st *stats.Stats, fn func(term clause.Clause) bool) int {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	negated := make([]clause.Clause, len(terms))
+	for i := range terms {
+		negated[i] = negate(terms[i])
+	}
+	implicants := saturate(negated, st, nil)
+	for i := range implicants {
+		implicants[i] = negate(implicants[i])
+	}
+	sort.Slice(implicants, func(i, k int) bool { return less(implicants[i], implicants[k]) })
+	return emit(implicants, maxSize, fn)
+}
+
+// StreamImplicants works like Implicants, but passes every prime implicant as soon as it is known,
+// see StreamImplicates.
+func StreamImplicants(terms []clause.Clause, maxSize int, st *stats.Stats, fn func(term clause.Clause) bool) int {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	negated := make([]clause.Clause, len(terms))
+	for i := range terms {
+		negated[i] = negate(terms[i])
+	}
+	return stream(negated, maxSize, st, func(c clause.Clause) bool {
+		return fn(negate(c))
+	})
+}
+
+// stream saturates the set and passes every kept clause of at most maxSize literals that is a
+// prime implicate of the set to fn. Returns the number of primes passed.
+func stream(set []clause.Clause, maxSize int, st *stats.Stats, fn func(c clause.Clause) bool) int {
+	s := incremental.New()
+	s.Add(set...)
+	count := 0
+	saturate(set, st, func(c clause.Clause) bool {
+		if maxSize > 0 && c.Size() > maxSize || !prime(s, c) {
+			return true
+		}
+		count++
+		return fn(c)
+	})
+	return count
+}
+
+// prime checks if an implicate of the clauses of the solver is prime,
+// i.e. if no clause with one literal less is an implicate.
+func prime(s *incremental.Solver, c clause.Clause) bool {
+	literals := c.Literals()
+	for i := range literals {
+		// The negation of c without its i-th literal
+		assumptions := []clause.Literal{}
+		for k, l := range literals {
+			if k != i {
+				assumptions = append(assumptions, -l)
+			}
+		}
+		if r, _ := s.Solve(context.Background(), assumptions, nil); r.Unsatisfiable {
+			return false
+		}
+	}
+	return true
+}
+
+// emit passes the sorted primes of at most maxSize literals to fn until it returns false.
+// Returns the number of primes passed.
+func emit(primes []clause.Clause, maxSize int, fn func(c clause.Clause) bool) int {
+	count := 0
+	for _, c := range primes {
+		if maxSize > 0 && c.Size() > maxSize {
+			// Primes are sorted by size
+			break
+		}
+		count++
+		if !fn(c) {
+			break
+		}
+	}
+	return count
+}
+
+// saturate resolves every pair of clauses until no resolvent is new, keeping only clauses that no
+// other clause subsumes. Returns the remaining clauses sorted.
+// If kept is not nil, it is called with every clause when it is kept, and the saturation stops
+// and returns nil as soon as kept returns false.
+func saturate(set []clause.Clause, st *stats.Stats, kept func(c clause.Clause) bool) []clause.Clause {
+	clauses := []clause.Clause{}
+	// add inserts a clause and reports it to kept, returning false if the saturation stops
+	add := func(c clause.Clause) bool {
+		var ok bool
+		clauses, ok = insert(clauses, c, st)
+		return !ok || kept == nil || kept(c)
+	}
+	for i := range set {
+		if !add(*set[i].Copy()) {
+			return nil
+		}
+	}
+	for {
+		st.Rounds++
+		resolvents := []clause.Clause{}
+		for i := range clauses {
+			for k := i + 1; k < len(clauses); k++ {
+				r, ok := clauses[i].Resolve(clauses[k])
+				if r == nil {
+					st.Tautologies++
+					continue
+				}
+				if ok && !subsumed(clauses, *r) && !subsumed(resolvents, *r) {
+					resolvents = append(resolvents, *r)
+				}
+			}
+		}
+		if len(resolvents) == 0 {
+			break
+		}
+		// A removed clause stays subsumed, so it is never derived again and the saturation ends
+		for _, r := range resolvents {
+			st.Resolvents++
+			if !add(r) {
+				return nil
+			}
+		}
+	}
+	sort.Slice(clauses, func(i, k int) bool { return less(clauses[i], clauses[k]) })
+	return clauses
+}
+
+// insert adds a clause to a set without subsumed clauses, removing the clauses it subsumes.
+// The clause is not added if a clause of the set subsumes it. Returns the set and whether c was added.
+func insert(clauses []clause.Clause, c clause.Clause, st *stats.Stats) ([]clause.Clause, bool) {
+	if subsumed(clauses, c) {
+		st.Subsumptions++
+		return clauses, false
+	}
+	kept := clauses[:0]
+	for _, d := range clauses {
+		if subset(c, d) {
+			st.Subsumptions++
+			continue
+		}
+		kept = append(kept, d)
+	}
+	return append(kept, c), true
+}
+
+// subsumed checks if a clause of the set contains only literals of c.
+func subsumed(clauses []clause.Clause, c clause.Clause) bool {
+	for _, d := range clauses {
+		if subset(d, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// subset checks if every literal of a is in b.
+func subset(a, b clause.Clause) bool {
+	if a.Size() > b.Size() {
+		return false
+	}
+	for _, l := range a.Literals() {
+		if !b.Contains(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// less orders clauses by size, then by their literals sorted by variable, a positive literal
+// before the negative literal of the same variable.
+func less(a, b clause.Clause) bool {
+	if a.Size() != b.Size() {
+		return a.Size() < b.Size()
+	}
+	x, y := a.Literals(), b.Literals()
+	for i := range x {
+		if x[i] == y[i] {
+			continue
+		}
+		if x[i].Var() != y[i].Var() {
+			return x[i].Var() < y[i].Var()
+		}
+		return x[i] > 0
+	}
+	return false
+}
+
+// negate returns the clause of the negated literals.
+func negate(c clause.Clause) clause.Clause {
+	result := clause.New()
+	for _, l := range c.Literals() {
+		result.Insert(-l)
+	}
+	return *result
+}
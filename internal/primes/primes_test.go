@@ -0,0 +1,205 @@
+package primes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+	"github.com/thxrsxm/res/internal/gen"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+func TestImplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		maxSize  int
+		expected []string
+	}{
+		{"empty clause set", []string{}, 0, []string{}},
+		{"unsatisfiable", []string{"A", "-A,B", "-B"}, 0, []string{"{}"}},
+		{"consensus", []string{"A,B", "-A,C"}, 0, []string{"{A, B}", "{-A, C}", "{B, C}"}},
+		{"subsumed input", []string{"A", "A,B", "-A,B,C"}, 0, []string{"{A}", "{B, C}"}},
+		{"chain", []string{"-A,B", "-B,C"}, 0, []string{"{-A, B}", "{-A, C}", "{-B, C}"}},
+		{"size limit", []string{"A,B,C", "-A,D"}, 2, []string{"{-A, D}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := []string{}
+			count := Implicates(clausetest.ParseSet(t, tt.clauses...), tt.maxSize, nil, func(c clause.Clause) bool {
+				result = append(result, c.String())
+				return true
+			})
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Implicates(%v) = %v; want %v", tt.clauses, result, tt.expected)
+			}
+			if count != len(result) {
+				t.Errorf("Implicates(%v) returned %d; want %d", tt.clauses, count, len(result))
+			}
+		})
+	}
+}
+
+func TestImplicatesStop(t *testing.T) {
+	count := Implicates(clausetest.ParseSet(t, "A,B", "-A,C"), 0, nil, func(c clause.Clause) bool {
+		return false
+	})
+	if count != 1 {
+		t.Errorf("Implicates() returned %d after fn returned false; want 1", count)
+	}
+}
+
+func TestImplicatesStats(t *testing.T) {
+	st := &stats.Stats{}
+	Implicates(clausetest.ParseSet(t, "A,B", "-A,B"), 0, st, func(c clause.Clause) bool { return true })
+	if st.Resolvents != 1 || st.Subsumptions != 2 {
+		t.Errorf("Implicates() stats = %+v; want 1 resolvent and 2 subsumptions", st)
+	}
+}
+
+func TestImplicatesBruteForce(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		set, err := gen.Random(3, 4, 3+int(seed%8), seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := []clause.Clause{}
+		Implicates(set, 0, nil, func(c clause.Clause) bool {
+			result = append(result, c)
+			return true
+		})
+		expected := bruteForce(set)
+		if len(result) != len(expected) {
+			t.Fatalf("seed %d: Implicates(%v) = %v; want %v", seed, set, result, expected)
+		}
+		for i := range expected {
+			if clause.Index(result, expected[i]) < 0 {
+				t.Errorf("seed %d: Implicates(%v) = %v; want %v", seed, set, result, expected)
+			}
+		}
+	}
+}
+
+func TestImplicants(t *testing.T) {
+	// A∧B ∨ ¬A∧C has the consensus B∧C as a further prime implicant
+	result := []string{}
+	Implicants(clausetest.ParseSet(t, "A,B", "-A,C"), 0, nil, func(term clause.Clause) bool {
+		result = append(result, term.String())
+		return true
+	})
+	expected := []string{"{A, B}", "{-A, C}", "{B, C}"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Implicants() = %v; want %v", result, expected)
+	}
+}
+
+func TestStream(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		set, err := gen.Random(3, 4, 3+int(seed%8), seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, maxSize := range []int{0, 2} {
+			expected := []clause.Clause{}
+			Implicates(set, maxSize, nil, func(c clause.Clause) bool {
+				expected = append(expected, c)
+				return true
+			})
+			result := []clause.Clause{}
+			count := StreamImplicates(set, maxSize, nil, func(c clause.Clause) bool {
+				result = append(result, c)
+				return true
+			})
+			if count != len(result) || len(result) != len(expected) {
+				t.Fatalf("seed %d: StreamImplicates(%v, %d) = %v; want %v", seed, set, maxSize, result, expected)
+			}
+			for i := range expected {
+				if clause.Index(result, expected[i]) < 0 {
+					t.Errorf("seed %d: StreamImplicates(%v, %d) = %v; want %v", seed, set, maxSize, result, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestStreamStop(t *testing.T) {
+	st := &stats.Stats{}
+	count := StreamImplicates(clausetest.ParseSet(t, "A,B", "-A,C", "-B,D"), 0, st, func(c clause.Clause) bool {
+		return false
+	})
+	// The first input clause is prime, so the saturation stops before resolving
+	if count != 1 || st.Resolvents != 0 {
+		t.Errorf("StreamImplicates() returned %d after %d resolvents; want 1 after 0", count, st.Resolvents)
+	}
+}
+
+func TestStreamImplicants(t *testing.T) {
+	result := []string{}
+	StreamImplicants(clausetest.ParseSet(t, "A,B", "-A,C"), 0, nil, func(term clause.Clause) bool {
+		result = append(result, term.String())
+		return true
+	})
+	expected := []string{"{A, B}", "{-A, C}", "{B, C}"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("StreamImplicants() = %v; want %v", result, expected)
+	}
+}
+
+// bruteForce returns the prime implicates of the set by checking every clause over its variables.
+func bruteForce(set []clause.Clause) []clause.Clause {
+	vars := clause.Variables(set)
+	implicates := []clause.Clause{}
+	// Every variable is left out, positive or negative
+	for n := 0; n < pow3(len(vars)); n++ {
+		c := clause.New()
+		for i, m := 0, n; i < len(vars); i, m = i+1, m/3 {
+			switch m % 3 {
+			case 1:
+				c.Insert(vars[i])
+			case 2:
+				c.Insert(-vars[i])
+			}
+		}
+		if implied(set, vars, *c) {
+			implicates = append(implicates, *c)
+		}
+	}
+	primes := []clause.Clause{}
+	for i := range implicates {
+		prime := true
+		for k := range implicates {
+			if k != i && implicates[k].Size() < implicates[i].Size() && subset(implicates[k], implicates[i]) {
+				prime = false
+				break
+			}
+		}
+		if prime {
+			primes = append(primes, implicates[i])
+		}
+	}
+	return primes
+}
+
+// implied checks if every model of the set satisfies c.
+func implied(set []clause.Clause, vars []clause.Literal, c clause.Clause) bool {
+	result := true
+	clause.Assignments(vars, func(assignment map[clause.Literal]bool) bool {
+		for i := range set {
+			if !set[i].Eval(assignment) {
+				return true
+			}
+		}
+		result = c.Eval(assignment)
+		return result
+	})
+	return result
+}
+
+func pow3(n int) int {
+	result := 1
+	for range n {
+		result *= 3
+	}
+	return result
+}
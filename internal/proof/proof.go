@@ -0,0 +1,171 @@
+// Package proof reads and checks resolution proofs written by hand in a simple line format.
+//
+// Every line of a proof has one of two forms:
+//
+//	n: clause              a premise, which must be one of the input clauses
+//	n: clause from i,j     the resolvent of the clauses of lines i and j
+//
+// Clauses are written in clause syntax (A,-B) or set notation ({A, -B}), the empty
+// clause as {}. Blank lines are ignored and a # starts a comment.
+package proof
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+// Line is a single step of a proof.
+type Line struct {
+	// Source is the line number in the input, starting at 1.
+	Source int
+	// Number is the number the proof gives to the clause.
+	Number int
+	// Clause is the clause of the step.
+	Clause clause.Clause
+	// Premise is true if the clause is taken from the input clauses.
+	Premise bool
+	// Parents are the numbers of the resolved clauses. They are only set if Premise is false.
+	Parents [2]int
+}
+
+// Error describes the first invalid line of a proof.
+type Error struct {
+	// Source is the line number in the input, or 0 if the proof as a whole is invalid.
+	Source int
+	// Reason explains why the line is invalid.
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.Source == 0 {
+		return e.Reason
+	}
+	return fmt.Sprintf("line %d: %s", e.Source, e.Reason)
+}
+
+// Read reads a proof. It returns an *Error for the first line that does not follow the format.
+func Read(r io.Reader) ([]Line, error) {
+	lines := []Line{}
+	scanner := bufio.NewScanner(r)
+	for source := 1; scanner.Scan(); source++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		line, err := parseLine(text)
+		if err != nil {
+			return nil, &Error{source, err.Error()}
+		}
+		line.Source = source
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseLine parses a line of the form n: clause [from i,j].
+func parseLine(text string) (Line, error) {
+	line := Line{Premise: true}
+	number, rest, ok := strings.Cut(text, ":")
+	if !ok {
+		return line, fmt.Errorf("expected \"n: clause\" or \"n: clause from i,j\"")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(number))
+	if err != nil || n < 1 {
+		return line, fmt.Errorf("invalid line number %q", strings.TrimSpace(number))
+	}
+	line.Number = n
+	body, parents, found := strings.Cut(rest, " from ")
+	if found {
+		line.Premise = false
+		i, j, ok := strings.Cut(parents, ",")
+		if !ok {
+			return line, fmt.Errorf("expected two clause numbers after \"from\", got %q", strings.TrimSpace(parents))
+		}
+		for k, s := range []string{i, j} {
+			p, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return line, fmt.Errorf("invalid clause number %q", strings.TrimSpace(s))
+			}
+			line.Parents[k] = p
+		}
+	}
+	c, err := parseClause(body)
+	if err != nil {
+		return line, err
+	}
+	line.Clause = *c
+	return line, nil
+}
+
+// parseClause parses a clause in clause syntax or set notation.
+func parseClause(s string) (*clause.Clause, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+		if s == "" {
+			return clause.New(), nil
+		}
+	}
+	c, err := clause.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("parsing clause %q: %v", s, err)
+	}
+	return c, nil
+}
+
+// Check validates a proof against the input clauses. Every premise must be one of the input clauses,
+// every resolvent must be what Clause.Resolve derives from two earlier lines, and some line must
+// derive the empty clause. It returns an *Error describing the first invalid line.
+func Check(inputs []clause.Clause, lines []Line) error {
+	clauses := map[int]clause.Clause{}
+	empty := false
+	for _, line := range lines {
+		if _, ok := clauses[line.Number]; ok {
+			return &Error{line.Source, fmt.Sprintf("clause %d is already defined", line.Number)}
+		}
+		if line.Premise {
+			if clause.Index(inputs, line.Clause) < 0 {
+				return &Error{line.Source, fmt.Sprintf("%s is not an input clause", line.Clause.String())}
+			}
+		} else if reason := checkStep(clauses, line); reason != "" {
+			return &Error{line.Source, reason}
+		}
+		clauses[line.Number] = line.Clause
+		empty = empty || line.Clause.IsEmpty()
+	}
+	if !empty {
+		return &Error{0, "the proof does not derive the empty clause"}
+	}
+	return nil
+}
+
+// checkStep returns why a resolution step is invalid, or an empty string if it is valid.
+func checkStep(clauses map[int]clause.Clause, line Line) string {
+	i, j := line.Parents[0], line.Parents[1]
+	for _, p := range line.Parents {
+		if _, ok := clauses[p]; !ok {
+			return fmt.Sprintf("clause %d is not defined before this line", p)
+		}
+	}
+	left, right := clauses[i], clauses[j]
+	resolvent, resolved := left.Resolve(right)
+	switch {
+	case resolvent == nil:
+		return fmt.Sprintf("clauses %d and %d clash on more than one literal, so they cannot be resolved", i, j)
+	case !resolved:
+		return fmt.Sprintf("clauses %d and %d have no complementary literals", i, j)
+	case !resolvent.Equals(line.Clause):
+		pivot, _ := left.Pivot(right)
+		return fmt.Sprintf("resolving clauses %d and %d on %s gives %s, not %s",
+			i, j, clause.Lit2Str(pivot.Var()), resolvent.String(), line.Clause.String())
+	}
+	return ""
+}
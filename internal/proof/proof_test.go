@@ -0,0 +1,108 @@
+package proof
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+)
+
+func TestRead(t *testing.T) {
+	input := "# proof\n1: A,B\n2: {-A, B}\n\n3: {B} from 1, 2 # resolve on A\n4: {} from 3,3\n"
+	lines, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("Read() returned %d lines; want 4", len(lines))
+	}
+	if !lines[0].Premise || lines[0].Number != 1 || lines[0].Source != 2 || lines[0].Clause.String() != "{A, B}" {
+		t.Errorf("line 0 = %+v; want premise 1: {A, B} from source line 2", lines[0])
+	}
+	if lines[2].Premise || lines[2].Parents != [2]int{1, 2} || lines[2].Clause.String() != "{B}" {
+		t.Errorf("line 2 = %+v; want {B} from 1,2", lines[2])
+	}
+	if !lines[3].Clause.IsEmpty() || lines[3].Source != 6 {
+		t.Errorf("line 3 = %+v; want the empty clause from source line 6", lines[3])
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"missing colon", "1: A\n2 A,B\n", "line 2: expected \"n: clause\" or \"n: clause from i,j\""},
+		{"invalid number", "x: A\n", "line 1: invalid line number \"x\""},
+		{"invalid clause", "1: A,1\n", "line 1: parsing clause \"A,1\": unknown symbol: \"1\""},
+		{"one parent", "1: A from 2\n", "line 1: expected two clause numbers after \"from\", got \"2\""},
+		{"invalid parent", "1: A from 2,b\n", "line 1: invalid clause number \"b\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.input))
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Read(%q) error = %v; want %q", tt.input, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	inputs := []string{"A,B", "-A,B", "A,-B", "-A,-B"}
+	tests := []struct {
+		name     string
+		proof    string
+		expected string
+	}{
+		{"valid", "1: A,B\n2: -A,B\n3: A,-B\n4: -A,-B\n5: B from 1,2\n6: -B from 3,4\n7: {} from 5,6\n", ""},
+		{"premise not an input", "1: A\n", "line 1: {A} is not an input clause"},
+		{"duplicate number", "1: A,B\n1: -A,B\n", "line 2: clause 1 is already defined"},
+		{"undefined parent", "1: A,B\n2: B from 1,3\n", "line 2: clause 3 is not defined before this line"},
+		{"later parent", "1: A,B\n2: B from 1,3\n3: -A,B\n", "line 2: clause 3 is not defined before this line"},
+		{"no complementary literals", "1: A,B\n2: A,-B\n3: A from 1,1\n", "line 3: clauses 1 and 1 have no complementary literals"},
+		{"two complementary pairs", "1: A,B\n2: -A,-B\n3: {} from 1,2\n", "line 3: clauses 1 and 2 clash on more than one literal, so they cannot be resolved"},
+		{"wrong resolvent", "1: A,B\n2: -A,B\n3: A,B from 1,2\n", "line 3: resolving clauses 1 and 2 on A gives {B}, not {A, B}"},
+		{"no empty clause", "1: A,B\n2: -A,B\n3: B from 1,2\n", "the proof does not derive the empty clause"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := Read(strings.NewReader(tt.proof))
+			if err != nil {
+				t.Fatalf("Read() failed: %v", err)
+			}
+			err = Check(clausetest.ParseSet(t, inputs...), lines)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Check() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Check() = %v; want %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckRefutation(t *testing.T) {
+	// Proofs built from clause.Refute are accepted
+	set := clausetest.ParseSet(t, "A,B,C", "A,B,-C", "A,-B", "-A,B", "-A,-B")
+	unsat, steps := clause.Refute(set, nil)
+	if !unsat {
+		t.Fatalf("Refute() = false; want true")
+	}
+	lines := []Line{}
+	for i := range set {
+		lines = append(lines, Line{Source: i + 1, Number: i + 1, Clause: set[i], Premise: true})
+	}
+	for _, step := range steps {
+		n := len(lines) + 1
+		lines = append(lines, Line{Source: n, Number: n, Clause: step.Resolvent, Parents: [2]int{step.Left + 1, step.Right + 1}})
+	}
+	if err := Check(set, lines); err != nil {
+		t.Errorf("Check() = %v; want nil", err)
+	}
+}
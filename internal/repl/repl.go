@@ -0,0 +1,420 @@
+// Package repl implements an interactive session for building and querying a clause set.
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/instance"
+	"github.com/thxrsxm/res/internal/solver"
+)
+
+// Help describes the commands of a session.
+const Help = `Commands:
+  add <clause> ...     Add clauses, e.g. add A,B -A
+  remove <n> ...       Remove clauses by number
+  list                 List the clauses with their numbers
+  sat                  Check if the clause set is satisfiable
+  entails <clause>     Check if the clause set entails a clause
+  proof [<clause>]     Print a resolution refutation of the clause set,
+                       or of the clause set and the negation of a clause
+  load <file>          Add the clauses of a file (.cnf files are read as DIMACS)
+  save <file>          Write the clauses to a file (.cnf files are written as DIMACS)
+  undo                 Revert the last add, remove or load of the innermost scope
+  push                 Open a scope
+  pop                  Close the innermost scope, retracting the changes made in it
+  help                 Print this help
+  quit                 End the session
+`
+
+// entry is a clause of the session together with its number.
+type entry struct {
+	number int
+	clause clause.Clause
+}
+
+// state is the part of a session that undo restores.
+type state struct {
+	entries []entry
+}
+
+// scope is an open scope of a session.
+type scope struct {
+	// saved is the state when the scope was opened
+	saved state
+	// history is the length of the undo history when the scope was opened
+	history int
+}
+
+// Session holds a numbered clause set that is changed and queried by commands.
+// Numbers are assigned in the order clauses are added and are not reused after a remove, pop or undo,
+// so proofs can refer to clauses by number.
+type Session struct {
+	// Prompt is printed before reading each command. No prompt is printed if it is empty.
+	Prompt  string
+	current state
+	history []state
+	scopes  []scope
+	// next is the number of the next clause. Undo does not restore it, so numbers are never reused.
+	next int
+}
+
+// New creates an empty session.
+func New() *Session {
+	return &Session{next: 1}
+}
+
+// Run reads commands line by line from r and writes their output to w
+// until r is exhausted or the quit command is read.
+// Errors of single commands are written to w and do not end the session.
+func (s *Session) Run(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for {
+		fmt.Fprint(w, s.Prompt)
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "quit" || line == "exit" {
+			return nil
+		}
+		if err := s.Exec(line, w); err != nil {
+			fmt.Fprintf(w, "Error: %v\n", err)
+		}
+	}
+	if s.Prompt != "" {
+		fmt.Fprintln(w)
+	}
+	return scanner.Err()
+}
+
+// Exec executes a single command and writes its output to w.
+// Empty lines and lines starting with # are ignored.
+func (s *Session) Exec(line string, w io.Writer) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil
+	}
+	name, args := fields[0], fields[1:]
+	switch name {
+	case "add":
+		return s.add(args, w)
+	case "remove":
+		return s.remove(args, w)
+	case "list":
+		return s.list(args, w)
+	case "sat":
+		return s.sat(args, w)
+	case "entails":
+		return s.entails(args, w)
+	case "proof":
+		return s.proof(args, w)
+	case "load":
+		return s.load(args, w)
+	case "save":
+		return s.save(args, w)
+	case "undo":
+		return s.undo(args, w)
+	case "push":
+		return s.push(args, w)
+	case "pop":
+		return s.pop(args, w)
+	case "help":
+		fmt.Fprint(w, Help)
+		return nil
+	default:
+		return fmt.Errorf("unknown command: %q (try help)", name)
+	}
+}
+
+// Clauses returns the clauses of the session in the order they were added.
+func (s *Session) Clauses() []clause.Clause {
+	set := make([]clause.Clause, len(s.current.entries))
+	for i, e := range s.current.entries {
+		set[i] = e.clause
+	}
+	return set
+}
+
+// checkpoint remembers the current state for undo.
+func (s *Session) checkpoint() {
+	s.history = append(s.history, s.current.copy())
+}
+
+// copy returns a copy of the state that does not share its entries.
+func (st state) copy() state {
+	return state{entries: append([]entry{}, st.entries...)}
+}
+
+// insert adds clauses to the session and prints them with their numbers.
+func (s *Session) insert(set []clause.Clause, w io.Writer) {
+	s.checkpoint()
+	for i := range set {
+		e := entry{s.next, set[i]}
+		s.current.entries = append(s.current.entries, e)
+		s.next++
+		fmt.Fprintf(w, "%d: %s\n", e.number, e.clause.String())
+	}
+}
+
+func (s *Session) add(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: add <clause> ...")
+	}
+	set := []clause.Clause{}
+	for _, arg := range args {
+		c, err := clause.Parse(arg)
+		if err != nil {
+			return fmt.Errorf("parsing clause %q: %v", arg, err)
+		}
+		set = append(set, *c)
+	}
+	s.insert(set, w)
+	return nil
+}
+
+func (s *Session) remove(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: remove <n> ...")
+	}
+	remove := map[int]bool{}
+	for _, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil || s.find(n) < 0 {
+			return fmt.Errorf("no clause with number %q", arg)
+		}
+		remove[n] = true
+	}
+	s.checkpoint()
+	kept := []entry{}
+	for _, e := range s.current.entries {
+		if remove[e.number] {
+			fmt.Fprintf(w, "removed %d: %s\n", e.number, e.clause.String())
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	s.current.entries = kept
+	return nil
+}
+
+// find returns the position of the clause with the given number, or -1 if there is none.
+func (s *Session) find(number int) int {
+	for i, e := range s.current.entries {
+		if e.number == number {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Session) list(args []string, w io.Writer) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: list")
+	}
+	if len(s.current.entries) == 0 {
+		fmt.Fprintln(w, "no clauses")
+	}
+	for _, e := range s.current.entries {
+		fmt.Fprintf(w, "%d: %s\n", e.number, e.clause.String())
+	}
+	return nil
+}
+
+func (s *Session) sat(args []string, w io.Writer) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: sat")
+	}
+	result := solver.Solve(s.Clauses())
+	if result.Unsatisfiable {
+		fmt.Fprintln(w, "[ ]")
+		return nil
+	}
+	fmt.Fprintln(w, "[x]")
+	if result.Model != nil {
+		model := clause.New()
+		for _, l := range result.Model {
+			model.Insert(l)
+		}
+		fmt.Fprintf(w, "model: %s\n", model.String())
+	}
+	return nil
+}
+
+// query parses the clause of an entailment query and returns its negation as unit clauses.
+func query(args []string, usage string) (*clause.Clause, []clause.Clause, error) {
+	if len(args) != 1 {
+		return nil, nil, fmt.Errorf("usage: %s", usage)
+	}
+	c, err := clause.Parse(args[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing clause %q: %v", args[0], err)
+	}
+	negated := []clause.Clause{}
+	for _, l := range c.Literals() {
+		unit := clause.New()
+		unit.Insert(-l)
+		negated = append(negated, *unit)
+	}
+	return c, negated, nil
+}
+
+func (s *Session) entails(args []string, w io.Writer) error {
+	_, negated, err := query(args, "entails <clause>")
+	if err != nil {
+		return err
+	}
+	// The set entails a clause if and only if adding its negation makes the set unsatisfiable
+	if solver.Solve(append(s.Clauses(), negated...)).Unsatisfiable {
+		fmt.Fprintln(w, "yes")
+	} else {
+		fmt.Fprintln(w, "no")
+	}
+	return nil
+}
+
+// proof prints a refutation in the line format n: clause [from i,j]. Premises are printed with
+// their session number, the units of a negated query and the resolvents are numbered after them.
+// Those numbers are reserved, so clauses added later do not reuse them.
+func (s *Session) proof(args []string, w io.Writer) error {
+	set := s.Clauses()
+	numbers := make([]int, len(set))
+	for i, e := range s.current.entries {
+		numbers[i] = e.number
+	}
+	var queried *clause.Clause
+	if len(args) > 0 {
+		c, negated, err := query(args, "proof [<clause>]")
+		if err != nil {
+			return err
+		}
+		queried = c
+		for i := range negated {
+			set = append(set, negated[i])
+			numbers = append(numbers, s.next+i)
+		}
+	}
+	unsat, steps := clause.Refute(set, nil)
+	if !unsat && queried != nil {
+		return fmt.Errorf("the clause set does not entail %s", queried.String())
+	}
+	if !unsat {
+		return fmt.Errorf("the clause set is satisfiable, so there is no refutation")
+	}
+	s.next = s.printProof(set, numbers, steps, w)
+	return nil
+}
+
+// printProof prints the premises used by the steps followed by the steps.
+// numbers holds the number of every clause of set. Returns the number after the last step.
+func (s *Session) printProof(set []clause.Clause, numbers []int, steps []clause.Step, w io.Writer) int {
+	used := make([]bool, len(set))
+	for _, step := range steps {
+		for _, parent := range []int{step.Left, step.Right} {
+			if parent < len(set) {
+				used[parent] = true
+			}
+		}
+	}
+	if len(steps) == 0 {
+		// The set contains the empty clause
+		used[clause.Index(set, *clause.New())] = true
+	}
+	for i := range set {
+		if !used[i] {
+			continue
+		}
+		if i >= len(s.current.entries) {
+			fmt.Fprintf(w, "%d: %s # negated query\n", numbers[i], set[i].String())
+		} else {
+			fmt.Fprintf(w, "%d: %s\n", numbers[i], set[i].String())
+		}
+	}
+	next := s.next + len(set) - len(s.current.entries)
+	for _, step := range steps {
+		numbers = append(numbers, next)
+		fmt.Fprintf(w, "%d: %s from %d,%d\n", next, step.Resolvent.String(), numbers[step.Left], numbers[step.Right])
+		next++
+	}
+	return next
+}
+
+func (s *Session) load(args []string, w io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: load <file>")
+	}
+	set, err := instance.Load(args[0])
+	if err != nil {
+		return err
+	}
+	s.insert(set, w)
+	return nil
+}
+
+func (s *Session) save(args []string, w io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: save <file>")
+	}
+	if err := instance.Save(args[0], s.Clauses()); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "saved %d clauses to %s\n", len(s.current.entries), args[0])
+	return nil
+}
+
+// undo reverts the last change. Changes made before the innermost open scope are only reverted
+// after its pop, so undo never changes the state a pop restores.
+func (s *Session) undo(args []string, w io.Writer) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: undo")
+	}
+	if len(s.scopes) > 0 && len(s.history) == s.scopes[len(s.scopes)-1].history {
+		return fmt.Errorf("nothing to undo since push")
+	}
+	if len(s.history) == 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+	s.current = s.history[len(s.history)-1]
+	s.history = s.history[:len(s.history)-1]
+	fmt.Fprintf(w, "%d clauses\n", len(s.current.entries))
+	return nil
+}
+
+func (s *Session) push(args []string, w io.Writer) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: push")
+	}
+	s.scopes = append(s.scopes, scope{s.current.copy(), len(s.history)})
+	fmt.Fprintf(w, "scope %d\n", len(s.scopes))
+	return nil
+}
+
+// pop restores the clauses of the innermost scope when it was opened. Clause numbers given out in
+// the scope are not reused, and undo cannot revert changes made in the scope any more.
+func (s *Session) pop(args []string, w io.Writer) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: pop")
+	}
+	if len(s.scopes) == 0 {
+		return fmt.Errorf("no scope to pop")
+	}
+	sc := s.scopes[len(s.scopes)-1]
+	s.scopes = s.scopes[:len(s.scopes)-1]
+	kept := map[int]bool{}
+	for _, e := range sc.saved.entries {
+		kept[e.number] = true
+	}
+	for _, e := range s.current.entries {
+		if !kept[e.number] {
+			fmt.Fprintf(w, "removed %d: %s\n", e.number, e.clause.String())
+		}
+	}
+	s.current.entries = sc.saved.entries
+	s.history = s.history[:sc.history]
+	fmt.Fprintf(w, "scope %d\n", len(s.scopes))
+	return nil
+}
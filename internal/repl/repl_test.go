@@ -0,0 +1,121 @@
+package repl
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			"add and list",
+			"add A,B -A\nlist\n",
+			"1: {A, B}\n2: {-A}\n1: {A, B}\n2: {-A}\n",
+		},
+		{
+			"empty list",
+			"list\n",
+			"no clauses\n",
+		},
+		{
+			"remove keeps numbers",
+			"add A B C\nremove 2\nadd D\nlist\n",
+			"1: {A}\n2: {B}\n3: {C}\nremoved 2: {B}\n4: {D}\n1: {A}\n3: {C}\n4: {D}\n",
+		},
+		{
+			"sat",
+			"add A,B -A\nsat\nadd -B\nsat\n",
+			"1: {A, B}\n2: {-A}\n[x]\nmodel: {-A, B}\n3: {-B}\n[ ]\n",
+		},
+		{
+			"entails",
+			"add A,B -A\nentails B\nentails A\n",
+			"1: {A, B}\n2: {-A}\nyes\nno\n",
+		},
+		{
+			"proof",
+			"add A,B -A,B C -B\nproof\n",
+			"1: {A, B}\n2: {-A, B}\n3: {C}\n4: {-B}\n" +
+				"1: {A, B}\n2: {-A, B}\n4: {-B}\n5: {B} from 1,2\n6: {} from 4,5\n",
+		},
+		{
+			"proof of a query",
+			"add A,B -A\nproof B\n",
+			"1: {A, B}\n2: {-A}\n1: {A, B}\n2: {-A}\n3: {-B} # negated query\n4: {B} from 1,2\n5: {} from 3,4\n",
+		},
+		{
+			"proof numbers are reserved",
+			"add A,B -A\nproof B\nadd C\nundo\nundo\nadd D\n",
+			"1: {A, B}\n2: {-A}\n1: {A, B}\n2: {-A}\n3: {-B} # negated query\n4: {B} from 1,2\n5: {} from 3,4\n" +
+				"6: {C}\n2 clauses\n0 clauses\n7: {D}\n",
+		},
+		{
+			"undo",
+			"add A\nadd B\nremove 1\nundo\nundo\nlist\nundo\nundo\n",
+			"1: {A}\n2: {B}\nremoved 1: {A}\n2 clauses\n1 clauses\n1: {A}\n0 clauses\nError: nothing to undo\n",
+		},
+		{
+			"errors",
+			"add A,1\nremove 7\nproof\nproof A\nfoo\n",
+			"Error: parsing clause \"A,1\": unknown symbol: \"1\"\n" +
+				"Error: no clause with number \"7\"\n" +
+				"Error: the clause set is satisfiable, so there is no refutation\n" +
+				"Error: the clause set does not entail {A}\n" +
+				"Error: unknown command: \"foo\" (try help)\n",
+		},
+		{
+			"push and pop",
+			"add A,B\npush\nadd -A -B\nsat\npop\nsat\nadd C\nlist\npop\n",
+			"1: {A, B}\nscope 1\n2: {-A}\n3: {-B}\n[ ]\nremoved 2: {-A}\nremoved 3: {-B}\nscope 0\n" +
+				"[x]\nmodel: {A, B}\n4: {C}\n1: {A, B}\n4: {C}\nError: no scope to pop\n",
+		},
+		{
+			"pop restores removed clauses",
+			"add A B\npush\nremove 1\npush\nadd C\npop\npop\nlist\nundo\nundo\n",
+			"1: {A}\n2: {B}\nscope 1\nremoved 1: {A}\nscope 2\n3: {C}\nremoved 3: {C}\nscope 1\nscope 0\n" +
+				"1: {A}\n2: {B}\n0 clauses\nError: nothing to undo\n",
+		},
+		{
+			"undo stops at push",
+			"add A\npush\nundo\nadd B\nundo\nundo\npop\nundo\n",
+			"1: {A}\nscope 1\nError: nothing to undo since push\n2: {B}\n1 clauses\n" +
+				"Error: nothing to undo since push\nscope 0\n0 clauses\n",
+		},
+		{
+			"quit and comments",
+			"# comment\n\nadd A\nquit\nadd B\n",
+			"1: {A}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := New().Run(strings.NewReader(tt.input), &out); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("Run(%q) printed\n%s\nwant\n%s", tt.input, out.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	for _, name := range []string{"set.txt", "set.cnf"} {
+		path := filepath.Join(t.TempDir(), name)
+		var out strings.Builder
+		s := New()
+		s.Run(strings.NewReader("add A,-B B\nsave "+path+"\n"), &out)
+		loaded := New()
+		out.Reset()
+		loaded.Run(strings.NewReader("load "+path+"\n"), &out)
+		if expected := "1: {A, -B}\n2: {B}\n"; out.String() != expected {
+			t.Errorf("load of %s printed %q; want %q", name, out.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+// Package solver selects a decision procedure for a set of clauses.
+package solver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/dp"
+	"github.com/thxrsxm/res/internal/dpll"
+	"github.com/thxrsxm/res/internal/drat"
+	"github.com/thxrsxm/res/internal/horn"
+	"github.com/thxrsxm/res/internal/stats"
+	"github.com/thxrsxm/res/internal/twosat"
+)
+
+// Names of the engines that can decide a clause set.
+const (
+	EngineAuto          = "auto"
+	EngineHorn          = "horn"
+	EngineTwoSAT        = "2-sat"
+	EngineRenamableHorn = "renamable-horn"
+	EngineResolution    = "resolution"
+	EngineDP            = "dp"
+	EngineDPLL          = "dpll"
+	EngineBruteForce    = "brute-force"
+	EnginePortfolio     = "portfolio"
+)
+
+// Result is the outcome of solving a clause set.
+type Result struct {
+	// Unsatisfiable is true if the clause set contains a contradiction.
+	Unsatisfiable bool
+	// Model lists the true literals of a satisfying assignment.
+	// It is nil if the clause set is unsatisfiable or the engine does not produce models.
+	Model []clause.Literal
+	// Conflict explains an unsatisfiable 2-CNF clause set. It is nil for every other engine.
+	Conflict *twosat.Conflict
+	// Engine is the name of the engine that decided the clause set.
+	// For the portfolio engine it is followed by a slash and the name of the search that answered first.
+	Engine string
+	// Stats describes the work of the engine.
+	Stats stats.Stats
+	// Proof is a DRAT proof of an unsatisfiable clause set.
+	// It is nil if the clause set is satisfiable or the engine does not produce proofs.
+	Proof *drat.Proof
+	// Refutation lists the resolution steps deriving the empty clause, as returned by clause.Refute.
+	// It is only set by the resolution engine for an unsatisfiable clause set.
+	Refutation []clause.Step
+}
+
+// Engine is a named decision procedure.
+type Engine struct {
+	// Name identifies the engine.
+	Name string
+	// Applies checks if the engine can decide the clause set.
+	Applies func(set []clause.Clause) bool
+	// Solve decides the clause set. It must only be called if Applies returns true.
+	// It stops when the context is done and returns its error. The horn, 2-sat and renamable-horn
+	// engines run in linear time and do not observe the context.
+	Solve func(ctx context.Context, set []clause.Clause, opts Options) (Result, error)
+}
+
+// Engines returns every available engine, starting with the automatic selection of Solve.
+func Engines() []Engine {
+	always := func([]clause.Clause) bool { return true }
+	renamable := func(set []clause.Clause) bool {
+		_, ok := horn.Renaming(set)
+		return ok
+	}
+	return []Engine{
+		{EngineAuto, always, solveAuto},
+		{EngineHorn, horn.IsHorn, solveHorn},
+		{EngineTwoSAT, twosat.IsTwoCNF, solveTwoSAT},
+		{EngineRenamableHorn, renamable, solveRenamableHorn},
+		{EngineResolution, always, solveResolution},
+		{EngineDP, always, solveDP},
+		{EngineDPLL, always, solveDPLL},
+		{EngineBruteForce, always, solveBruteForce},
+		{EnginePortfolio, always, portfolio},
+	}
+}
+
+// Lookup returns the engine with the given name.
+func Lookup(name string) (Engine, bool) {
+	for _, e := range Engines() {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return Engine{}, false
+}
+
+// Options configures the engines.
+type Options struct {
+	// Workers is the number of goroutines resolution saturation spreads the pairs of a clause over.
+	// Values below 2 saturate sequentially. The result is the same for every worker count.
+	Workers int
+}
+
+// Solve decides a set of clauses with the most specific engine available.
+// Horn, 2-CNF and renamable Horn clause sets are decided in linear time,
+// every other clause set goes through clause.Res.
+func Solve(set []clause.Clause) Result {
+	return SolveOptions(set, Options{})
+}
+
+// SolveOptions works like Solve with the given options.
+func SolveOptions(set []clause.Clause, opts Options) Result {
+	result, _ := solveAuto(context.Background(), set, opts)
+	return result
+}
+
+// SolveEngine decides a clause set with the named engine and the given options.
+// It returns an error if there is no such engine or it cannot decide the clause set,
+// and ctx.Err() if the context is done before the answer is found.
+func SolveEngine(ctx context.Context, name string, set []clause.Clause, opts Options) (Result, error) {
+	e, ok := Lookup(name)
+	if !ok {
+		return Result{}, fmt.Errorf("unknown engine: %q", name)
+	}
+	if !e.Applies(set) {
+		return Result{}, fmt.Errorf("engine %s cannot decide the clause set", name)
+	}
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+	return e.Solve(ctx, set, opts)
+}
+
+// run records the size of the input, calls solve and records the elapsed time.
+func run(set []clause.Clause, solve func(st *stats.Stats) Result) Result {
+	st := stats.Stats{}
+	st.Clauses(len(set))
+	for i := range set {
+		st.ClauseSize(set[i].Size())
+	}
+	start := time.Now()
+	result := solve(&st)
+	st.Elapsed = time.Since(start)
+	result.Stats = st
+	return result
+}
+
+// solveAuto decides a clause set with the most specific engine available, see Solve.
+func solveAuto(ctx context.Context, set []clause.Clause, opts Options) (Result, error) {
+	if horn.IsHorn(set) {
+		return solveHorn(ctx, set, opts)
+	}
+	if twosat.IsTwoCNF(set) {
+		return solveTwoSAT(ctx, set, opts)
+	}
+	if _, ok := horn.Renaming(set); ok {
+		return solveRenamableHorn(ctx, set, opts)
+	}
+	return resolution(ctx, set, opts.Workers)
+}
+
+// solveHorn decides a Horn clause set by forward chaining.
+// Forward chaining is unit propagation, so the empty clause alone is a proof.
+func solveHorn(_ context.Context, set []clause.Clause, _ Options) (Result, error) {
+	return run(set, func(st *stats.Stats) Result {
+		unsat, model := horn.Solve(set, st)
+		return Result{Unsatisfiable: unsat, Model: model, Engine: EngineHorn, Proof: propagationProof(unsat)}
+	}), nil
+}
+
+// solveTwoSAT decides a 2-CNF clause set using its implication graph.
+// The proof of a conflict on x adds -x, which follows by propagating along the path from x to -x,
+// and then the empty clause, which follows by propagating along the path back.
+func solveTwoSAT(_ context.Context, set []clause.Clause, _ Options) (Result, error) {
+	return run(set, func(st *stats.Stats) Result {
+		unsat, model, conflict := twosat.Solve(set, st)
+		proof := propagationProof(unsat)
+		if conflict != nil {
+			unit := clause.New()
+			unit.Insert(-conflict.Literal)
+			proof = &drat.Proof{}
+			proof.Add(*unit)
+			proof.Add(*clause.New())
+		}
+		return Result{Unsatisfiable: unsat, Model: model, Conflict: conflict, Engine: EngineTwoSAT, Proof: proof}
+	}), nil
+}
+
+// solveRenamableHorn decides a renamable Horn clause set by forward chaining on the renamed set.
+// Renaming does not change unit propagation, so the empty clause alone is a proof.
+func solveRenamableHorn(_ context.Context, set []clause.Clause, _ Options) (Result, error) {
+	return run(set, func(st *stats.Stats) Result {
+		unsat, model, _ := horn.SolveRenamable(set, st)
+		return Result{Unsatisfiable: unsat, Model: model, Engine: EngineRenamableHorn, Proof: propagationProof(unsat)}
+	}), nil
+}
+
+// solveResolution decides a clause set by saturation with clause.Res.
+func solveResolution(ctx context.Context, set []clause.Clause, opts Options) (Result, error) {
+	return resolution(ctx, set, opts.Workers)
+}
+
+// resolution decides a clause set by saturation on the given number of workers.
+// The proof adds the resolvents of the refutation in order, each follows from its parents by unit propagation.
+func resolution(ctx context.Context, set []clause.Clause, workers int) (Result, error) {
+	var err error
+	result := run(set, func(st *stats.Stats) Result {
+		var unsat bool
+		var steps []clause.Step
+		unsat, steps, err = clause.RefuteContext(ctx, set, workers, st)
+		if !unsat {
+			return Result{Engine: EngineResolution}
+		}
+		proof := &drat.Proof{}
+		for _, step := range steps {
+			proof.Add(step.Resolvent)
+		}
+		if len(steps) == 0 {
+			// The set contains the empty clause
+			proof.Add(*clause.New())
+		}
+		return Result{Unsatisfiable: true, Engine: EngineResolution, Proof: proof, Refutation: steps}
+	})
+	return result, err
+}
+
+// solveDP decides a clause set with the Davis–Putnam procedure.
+func solveDP(ctx context.Context, set []clause.Clause, _ Options) (Result, error) {
+	var err error
+	result := run(set, func(st *stats.Stats) Result {
+		proof := &drat.Proof{}
+		var unsat bool
+		unsat, _, err = dp.SolveContext(ctx, set, st, proof)
+		if !unsat {
+			proof = nil
+		}
+		return Result{Unsatisfiable: unsat, Engine: EngineDP, Proof: proof}
+	})
+	return result, err
+}
+
+// solveDPLL decides a clause set with the DPLL procedure.
+func solveDPLL(ctx context.Context, set []clause.Clause, _ Options) (Result, error) {
+	return dpllContext(ctx, set, EngineDPLL, dpll.Options{})
+}
+
+// dpllContext decides a clause set with the DPLL procedure configured by opts and reports the given
+// engine name. It stops when the context is done and returns its error.
+func dpllContext(ctx context.Context, set []clause.Clause, name string, opts dpll.Options) (Result, error) {
+	var err error
+	result := run(set, func(st *stats.Stats) Result {
+		proof := &drat.Proof{}
+		var unsat bool
+		var model []clause.Literal
+		unsat, model, err = dpll.SolveContext(ctx, set, opts, st, proof)
+		if !unsat {
+			proof = nil
+		}
+		return Result{Unsatisfiable: unsat, Model: model, Engine: name, Proof: proof}
+	})
+	return result, err
+}
+
+// solveBruteForce decides a clause set by evaluating every assignment.
+func solveBruteForce(ctx context.Context, set []clause.Clause, _ Options) (Result, error) {
+	var err error
+	result := run(set, func(st *stats.Stats) Result {
+		var unsat bool
+		var model []clause.Literal
+		unsat, model, err = clause.BruteForceContext(ctx, set)
+		return Result{Unsatisfiable: unsat, Model: model, Engine: EngineBruteForce}
+	})
+	return result, err
+}
+
+// search is a member of the portfolio.
+type search func(ctx context.Context, set []clause.Clause) (Result, error)
+
+// searches returns the members of the portfolio: resolution saturation, DPLL deciding variables
+// in ascending order and by number of occurrences, and DPLL with two random orders.
+func searches(opts Options) []search {
+	members := []search{
+		func(ctx context.Context, set []clause.Clause) (Result, error) {
+			return resolution(ctx, set, opts.Workers)
+		},
+		func(ctx context.Context, set []clause.Clause) (Result, error) {
+			return dpllContext(ctx, set, EngineDPLL, dpll.Options{})
+		},
+		func(ctx context.Context, set []clause.Clause) (Result, error) {
+			return dpllContext(ctx, set, EngineDPLL+"-occurrences", dpll.Options{Occurrences: true})
+		},
+	}
+	for seed := int64(1); seed <= 2; seed++ {
+		members = append(members, func(ctx context.Context, set []clause.Clause) (Result, error) {
+			return dpllContext(ctx, set, EngineDPLL+"-seed-"+strconv.FormatInt(seed, 10), dpll.Options{Seed: seed})
+		})
+	}
+	return members
+}
+
+// portfolio runs every search of the portfolio concurrently on its own copy of the clause set.
+// The first answer wins, the other searches are cancelled and waited for.
+// If the context is done first, every search is cancelled and waited for and its error is returned.
+func portfolio(parent context.Context, set []clause.Clause, opts Options) (Result, error) {
+	start := time.Now()
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	members := searches(opts)
+	answers := make(chan Result, len(members))
+	var wg sync.WaitGroup
+	for _, member := range members {
+		copied := make([]clause.Clause, len(set))
+		for i := range set {
+			copied[i] = *set[i].Copy()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// A search only fails if it is cancelled
+			if result, err := member(ctx, copied); err == nil {
+				answers <- result
+			}
+		}()
+	}
+	var winner Result
+	select {
+	case winner = <-answers:
+	case <-ctx.Done():
+	}
+	cancel()
+	wg.Wait()
+	if winner.Engine == "" {
+		return Result{}, parent.Err()
+	}
+	winner.Engine = EnginePortfolio + "/" + winner.Engine
+	winner.Stats.Elapsed = time.Since(start)
+	return winner, nil
+}
+
+// propagationProof returns the proof of a clause set refuted by unit propagation alone,
+// which only adds the empty clause, or nil if the set is satisfiable.
+func propagationProof(unsat bool) *drat.Proof {
+	if !unsat {
+		return nil
+	}
+	proof := &drat.Proof{}
+	proof.Add(*clause.New())
+	return proof
+}
@@ -0,0 +1,251 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+	"github.com/thxrsxm/res/internal/drat"
+	"github.com/thxrsxm/res/internal/gen"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name           string
+		clauses        []string
+		expected       bool
+		expectedEngine string
+	}{
+		{"empty clause set", []string{}, false, EngineHorn},
+		{"Horn satisfiable", []string{"A", "-A,B"}, false, EngineHorn},
+		{"Horn unsatisfiable", []string{"A", "-A,B", "-B"}, true, EngineHorn},
+		{"2-SAT satisfiable", []string{"A,B", "-A"}, false, EngineTwoSAT},
+		{"2-SAT unsatisfiable", []string{"A,B", "-A,B", "A,-B", "-A,-B"}, true, EngineTwoSAT},
+		{"renamable Horn", []string{"A,B,C", "-A", "-B"}, false, EngineRenamableHorn},
+		{"renamable Horn unsatisfiable", []string{"A,B,C", "-A", "-B", "-C"}, true, EngineRenamableHorn},
+		{"general satisfiable", []string{"A,B,C", "-A,-B,-C"}, false, EngineResolution},
+		{"general unsatisfiable", []string{"A,B,C", "A,B,-C", "A,-B", "-A,B", "-A,-B"}, true, EngineResolution},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Solve(clausetest.ParseSet(t, tt.clauses...))
+			if result.Unsatisfiable != tt.expected {
+				t.Errorf("Solve(%v).Unsatisfiable = %v; want %v", tt.clauses, result.Unsatisfiable, tt.expected)
+			}
+			if result.Engine != tt.expectedEngine {
+				t.Errorf("Solve(%v).Engine = %q; want %q", tt.clauses, result.Engine, tt.expectedEngine)
+			}
+			if result.Unsatisfiable && result.Engine == EngineTwoSAT && result.Conflict == nil {
+				t.Errorf("Solve(%v).Conflict = nil; want an explanation", tt.clauses)
+			}
+			if result.Model != nil && !clausetest.Satisfies(clausetest.ParseSet(t, tt.clauses...), result.Model) {
+				t.Errorf("Solve(%v).Model = %v does not satisfy the clause set", tt.clauses, result.Model)
+			}
+		})
+	}
+}
+
+func TestEngines(t *testing.T) {
+	sets := [][]string{
+		{},
+		{"A", "-A,B", "-B"},
+		{"A,B", "-A"},
+		{"A,B", "-A,B", "A,-B", "-A,-B"},
+		{"A,B,C", "-A", "-B"},
+		{"A,B,C", "-A,-B,-C", "A,-B", "-A,C"},
+		{"A,B,C", "A,B,-C", "A,-B", "-A,B", "-A,-B"},
+	}
+	for _, clauses := range sets {
+		expected, _ := clause.BruteForce(clausetest.ParseSet(t, clauses...))
+		for _, e := range Engines() {
+			set := clausetest.ParseSet(t, clauses...)
+			if !e.Applies(set) {
+				continue
+			}
+			result, err := e.Solve(context.Background(), set, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Unsatisfiable != expected {
+				t.Errorf("engine %s: Solve(%v) = %v; want %v", e.Name, clauses, result.Unsatisfiable, expected)
+			}
+			if result.Model != nil && !clausetest.Satisfies(set, result.Model) {
+				t.Errorf("engine %s: model %v does not satisfy %v", e.Name, result.Model, clauses)
+			}
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	clauses := []string{"A,B,C", "A,B,-C", "A,-B", "-A,B", "-A,-B"}
+	for _, e := range Engines() {
+		set := clausetest.ParseSet(t, clauses...)
+		if !e.Applies(set) {
+			continue
+		}
+		result, err := e.Solve(context.Background(), set, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		st := result.Stats
+		if st.PeakClauses < len(clauses) || st.MaxClauseSize != 3 {
+			t.Errorf("engine %s: stats %+v; want at least %d peak clauses and max clause size 3", e.Name, st, len(clauses))
+		}
+	}
+	result, err := solveResolution(context.Background(), clausetest.ParseSet(t, clauses...), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := result.Stats; st.Resolvents == 0 || st.Rounds == 0 {
+		t.Errorf("solveResolution() stats %+v; want resolvents and rounds", st)
+	}
+	result, err = solveDPLL(context.Background(), clausetest.ParseSet(t, clauses...), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := result.Stats; st.Decisions == 0 || st.Conflicts == 0 {
+		t.Errorf("solveDPLL() stats %+v; want decisions and conflicts", st)
+	}
+}
+
+func TestProofs(t *testing.T) {
+	sets := [][]string{
+		{"A", "-A,B", "-B"},
+		{"A,B", "-A,B", "A,-B", "-A,-B"},
+		{"A,B,C", "-A", "-B", "-C"},
+		{"A,B,C", "A,B,-C", "A,-B", "-A,B", "-A,-B"},
+		{"A,B,C", "-A,B", "-B,C", "-C,A", "-A,-B,-C"},
+	}
+	for seed := int64(1); seed <= 20; seed++ {
+		// Random 3-CNF over 4 variables with 20 clauses is mostly unsatisfiable
+		set, err := gen.Random(3, 4, 20, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if unsat, _ := clause.BruteForce(set); unsat {
+			clauses := []string{}
+			for i := range set {
+				clauses = append(clauses, strings.Trim(set[i].String(), "{}"))
+			}
+			sets = append(sets, clauses)
+		}
+	}
+	for _, clauses := range sets {
+		for _, e := range Engines() {
+			set := clausetest.ParseSet(t, clauses...)
+			if !e.Applies(set) {
+				continue
+			}
+			result, err := e.Solve(context.Background(), set, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.Unsatisfiable {
+				t.Fatalf("engine %s: Solve(%v) = false; want true", e.Name, clauses)
+			}
+			if result.Proof == nil {
+				if e.Name != EngineBruteForce {
+					t.Errorf("engine %s: no proof for %v", e.Name, clauses)
+				}
+				continue
+			}
+			if err := drat.Check(set, result.Proof); err != nil {
+				t.Errorf("engine %s: proof for %v rejected: %v", e.Name, clauses, err)
+			}
+		}
+	}
+	set := clausetest.ParseSet(t, sets[3]...)
+	if result, _ := solveResolution(context.Background(), set, Options{}); len(result.Refutation) == 0 || !result.Refutation[len(result.Refutation)-1].Resolvent.IsEmpty() {
+		t.Errorf("solveResolution() refutation %v does not end with the empty clause", result.Refutation)
+	}
+	if result := Solve(clausetest.ParseSet(t, "A,B", "-A")); result.Proof != nil {
+		t.Errorf("Solve() of a satisfiable set returned a proof")
+	}
+}
+
+func TestSolveOptionsWorkers(t *testing.T) {
+	clauses := []string{"A,B,C", "A,B,-C", "A,-B", "-A,B", "-A,-B"}
+	expected := Solve(clausetest.ParseSet(t, clauses...))
+	for _, workers := range []int{0, 2, 4} {
+		set := clausetest.ParseSet(t, clauses...)
+		result := SolveOptions(set, Options{Workers: workers})
+		result.Stats.Elapsed = expected.Stats.Elapsed
+		if result.Unsatisfiable != expected.Unsatisfiable || result.Stats != expected.Stats {
+			t.Errorf("SolveOptions(%d workers) = %v with %+v; want %v with %+v",
+				workers, result.Unsatisfiable, result.Stats, expected.Unsatisfiable, expected.Stats)
+		}
+		if fmt.Sprint(result.Refutation) != fmt.Sprint(expected.Refutation) {
+			t.Errorf("SolveOptions(%d workers) refutation = %v; want %v", workers, result.Refutation, expected.Refutation)
+		}
+		if err := drat.Check(set, result.Proof); err != nil {
+			t.Errorf("SolveOptions(%d workers) proof rejected: %v", workers, err)
+		}
+	}
+}
+
+func TestPortfolio(t *testing.T) {
+	members := []string{"resolution", "dpll", "dpll-occurrences", "dpll-seed-1", "dpll-seed-2"}
+	for _, clauses := range [][]string{
+		{"A,B,C", "-A,-B,-C", "A,-B", "-A,C"},
+		{"A,B,C", "A,B,-C", "A,-B", "-A,B", "-A,-B"},
+	} {
+		set := clausetest.ParseSet(t, clauses...)
+		result, err := SolveEngine(context.Background(), EnginePortfolio, set, Options{Workers: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := clause.BruteForce(set)
+		if result.Unsatisfiable != expected {
+			t.Errorf("portfolio: Solve(%v) = %v; want %v", clauses, result.Unsatisfiable, expected)
+		}
+		winner, ok := strings.CutPrefix(result.Engine, EnginePortfolio+"/")
+		if !ok || !slices.Contains(members, winner) {
+			t.Errorf("portfolio: Engine = %q; want %s/ followed by one of %v", result.Engine, EnginePortfolio, members)
+		}
+		if result.Unsatisfiable {
+			if err := drat.Check(set, result.Proof); err != nil {
+				t.Errorf("portfolio: proof of %s rejected: %v", winner, err)
+			}
+		}
+	}
+}
+
+func TestPortfolioCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := portfolio(ctx, clausetest.ParseSet(t, "A,B", "-A,B"), Options{}); err != context.Canceled {
+		t.Errorf("portfolio() with a cancelled context returned %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestSolveEngine(t *testing.T) {
+	set := clausetest.ParseSet(t, "A,B,C", "-A,-B,-C")
+	if _, err := SolveEngine(context.Background(), "unknown", set, Options{}); err == nil {
+		t.Errorf("SolveEngine(\"unknown\") returned no error")
+	}
+	if _, err := SolveEngine(context.Background(), EngineHorn, set, Options{}); err == nil {
+		t.Errorf("SolveEngine(%q) of a non-Horn set returned no error", EngineHorn)
+	}
+	for _, name := range []string{EngineAuto, EngineResolution, EngineDPLL} {
+		result, err := SolveEngine(context.Background(), name, set, Options{Workers: 2})
+		if err != nil || result.Unsatisfiable {
+			t.Errorf("SolveEngine(%q) = %v, %v; want satisfiable", name, result.Unsatisfiable, err)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, e := range Engines() {
+		found, ok := Lookup(e.Name)
+		if !ok || found.Name != e.Name {
+			t.Errorf("Lookup(%q) = %q, %v; want %q, true", e.Name, found.Name, ok, e.Name)
+		}
+	}
+	if _, ok := Lookup("unknown"); ok {
+		t.Errorf("Lookup(\"unknown\") = true; want false")
+	}
+}
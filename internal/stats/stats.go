@@ -0,0 +1,70 @@
+// Package stats collects counters describing the work of an engine.
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Stats collects counters describing the work of an engine.
+// Every engine fills the counters that apply to it and leaves the others at zero.
+type Stats struct {
+	// Rounds is the number of saturation rounds or eliminated variables.
+	Rounds int `json:"rounds"`
+	// Resolvents is the number of resolution steps that produced a new clause.
+	Resolvents int `json:"resolvents"`
+	// Duplicates is the number of resolvents discarded because they were already known.
+	Duplicates int `json:"duplicates"`
+	// Tautologies is the number of resolutions discarded because the resolvent would be a tautology.
+	Tautologies int `json:"tautologies"`
+	// Subsumptions is the number of clauses removed because another clause subsumes them.
+	Subsumptions int `json:"subsumptions"`
+	// Decisions is the number of branching decisions.
+	Decisions int `json:"decisions"`
+	// Conflicts is the number of falsified clauses found during search.
+	Conflicts int `json:"conflicts"`
+	// Propagations is the number of literals assigned by propagation.
+	Propagations int `json:"propagations"`
+	// PeakClauses is the largest number of clauses held at the same time.
+	PeakClauses int `json:"peak_clauses"`
+	// MaxClauseSize is the number of literals of the largest clause seen.
+	MaxClauseSize int `json:"max_clause_size"`
+	// Elapsed is the time the engine took.
+	Elapsed time.Duration `json:"elapsed_ns"`
+}
+
+// Clauses records the current number of clauses, keeping the peak.
+func (s *Stats) Clauses(n int) {
+	s.PeakClauses = max(s.PeakClauses, n)
+}
+
+// ClauseSize records the size of a clause, keeping the largest.
+func (s *Stats) ClauseSize(n int) {
+	s.MaxClauseSize = max(s.MaxClauseSize, n)
+}
+
+// String returns the counters as aligned text, one per line.
+func (s *Stats) String() string {
+	rows := []struct {
+		name  string
+		value any
+	}{
+		{"rounds", s.Rounds},
+		{"resolvents", s.Resolvents},
+		{"duplicates", s.Duplicates},
+		{"tautologies", s.Tautologies},
+		{"subsumptions", s.Subsumptions},
+		{"decisions", s.Decisions},
+		{"conflicts", s.Conflicts},
+		{"propagations", s.Propagations},
+		{"peak clauses", s.PeakClauses},
+		{"max clause size", s.MaxClauseSize},
+		{"elapsed", s.Elapsed},
+	}
+	var sb strings.Builder
+	for _, r := range rows {
+		fmt.Fprintf(&sb, "%-16s %v\n", r.name+":", r.value)
+	}
+	return sb.String()
+}
@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClauses(t *testing.T) {
+	s := &Stats{}
+	for _, n := range []int{3, 7, 5} {
+		s.Clauses(n)
+	}
+	if s.PeakClauses != 7 {
+		t.Errorf("PeakClauses = %d; want 7", s.PeakClauses)
+	}
+}
+
+func TestClauseSize(t *testing.T) {
+	s := &Stats{}
+	for _, n := range []int{2, 1, 4, 3} {
+		s.ClauseSize(n)
+	}
+	if s.MaxClauseSize != 4 {
+		t.Errorf("MaxClauseSize = %d; want 4", s.MaxClauseSize)
+	}
+}
+
+func TestString(t *testing.T) {
+	s := &Stats{Rounds: 2, Resolvents: 5, PeakClauses: 9, Elapsed: 1500 * time.Microsecond}
+	result := s.String()
+	for _, line := range []string{"rounds:          2\n", "resolvents:      5\n", "peak clauses:    9\n", "elapsed:         1.5ms\n"} {
+		if !strings.Contains(result, line) {
+			t.Errorf("String() = %q; want it to contain %q", result, line)
+		}
+	}
+	if lines := strings.Count(result, "\n"); lines != 11 {
+		t.Errorf("String() has %d lines; want 11", lines)
+	}
+}
@@ -0,0 +1,175 @@
+// Package tracecheck writes and checks resolution refutations in the TraceCheck format.
+//
+// Every line of a trace lists a clause together with its antecedents:
+//
+//	id literals 0 antecedents 0
+//
+// Input clauses have no antecedents. Every other clause is the result of resolving its
+// antecedents one after another. Literals use the DIMACS numbering: A is 1, B is 2,
+// and a negative number is a negated variable.
+package tracecheck
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+// Line is a clause of a trace.
+type Line struct {
+	// ID is the number of the clause.
+	ID int
+	// Clause lists the literals of the clause.
+	Clause []clause.Literal
+	// Antecedents are the numbers of the clauses resolved to derive the clause.
+	// They are empty for input clauses.
+	Antecedents []int
+}
+
+// FromRefutation converts a refutation of the set, as returned by clause.Refute, into a trace.
+// The input clauses are numbered from 1 in order and step i derives clause len(set)+i+1.
+func FromRefutation(set []clause.Clause, steps []clause.Step) []Line {
+	lines := []Line{}
+	for i := range set {
+		lines = append(lines, Line{ID: i + 1, Clause: set[i].Literals(), Antecedents: []int{}})
+	}
+	for i, step := range steps {
+		lines = append(lines, Line{
+			ID:          len(set) + i + 1,
+			Clause:      step.Resolvent.Literals(),
+			Antecedents: []int{step.Left + 1, step.Right + 1},
+		})
+	}
+	return lines
+}
+
+// Write writes the lines of a trace.
+//
+// Example: the input clauses {A, B} and {-A, B} and their resolvent {B} are written as
+//
+//	1 1 2 0 0
+//	2 -1 2 0 0
+//	3 2 0 1 2 0
+func Write(w io.Writer, lines []Line) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range lines {
+		fmt.Fprintf(bw, "%d ", line.ID)
+		for _, l := range line.Clause {
+			fmt.Fprintf(bw, "%d ", l)
+		}
+		fmt.Fprint(bw, "0 ")
+		for _, a := range line.Antecedents {
+			fmt.Fprintf(bw, "%d ", a)
+		}
+		fmt.Fprintln(bw, "0")
+	}
+	return bw.Flush()
+}
+
+// Read reads the lines of a trace.
+func Read(r io.Reader) ([]Line, error) {
+	lines := []Line{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		numbers := make([]int, len(fields))
+		for i, field := range fields {
+			v, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q", n, field)
+			}
+			numbers[i] = v
+		}
+		line := Line{ID: numbers[0], Clause: []clause.Literal{}, Antecedents: []int{}}
+		rest := numbers[1:]
+		k := 0
+		for ; k < len(rest) && rest[k] != 0; k++ {
+			line.Clause = append(line.Clause, clause.Literal(rest[k]))
+		}
+		if k == len(rest) {
+			return nil, fmt.Errorf("line %d: clause is not terminated by 0", n)
+		}
+		for k++; k < len(rest) && rest[k] != 0; k++ {
+			line.Antecedents = append(line.Antecedents, rest[k])
+		}
+		if k != len(rest)-1 {
+			return nil, fmt.Errorf("line %d: antecedents are not terminated by 0", n)
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// Check verifies a trace of a refutation of the clause set. Clauses without antecedents must be
+// input clauses, every other clause must be the result of resolving its antecedents, which must
+// be listed earlier, from left to right, and some clause must be empty.
+// Returns an error describing the first invalid line.
+func Check(set []clause.Clause, lines []Line) error {
+	clauses := map[int]clause.Clause{}
+	empty := false
+	for _, line := range lines {
+		if _, ok := clauses[line.ID]; ok {
+			return fmt.Errorf("clause %d: defined twice", line.ID)
+		}
+		c := toClause(line.Clause)
+		if len(line.Antecedents) == 0 {
+			if clause.Index(set, c) < 0 {
+				return fmt.Errorf("clause %d: %s is not an input clause", line.ID, c.String())
+			}
+		} else if err := chain(clauses, line.Antecedents, c); err != nil {
+			return fmt.Errorf("clause %d: %v", line.ID, err)
+		}
+		clauses[line.ID] = c
+		empty = empty || c.IsEmpty()
+	}
+	if !empty {
+		return fmt.Errorf("the trace does not derive the empty clause")
+	}
+	return nil
+}
+
+// chain resolves the antecedents from left to right and compares the result with c.
+func chain(clauses map[int]clause.Clause, antecedents []int, c clause.Clause) error {
+	if len(antecedents) < 2 {
+		return fmt.Errorf("a derived clause needs at least two antecedents")
+	}
+	var result *clause.Clause
+	for _, a := range antecedents {
+		next, ok := clauses[a]
+		if !ok {
+			return fmt.Errorf("antecedent %d is not defined before", a)
+		}
+		if result == nil {
+			result = next.Copy()
+			continue
+		}
+		resolvent, resolved := result.Resolve(next)
+		if !resolved || resolvent == nil {
+			return fmt.Errorf("antecedent %d does not clash with %s on exactly one literal", a, result.String())
+		}
+		result = resolvent
+	}
+	if !result.Equals(c) {
+		return fmt.Errorf("resolving the antecedents gives %s, not %s", result.String(), c.String())
+	}
+	return nil
+}
+
+// toClause converts literals into a clause.
+func toClause(literals []clause.Literal) clause.Clause {
+	c := clause.New()
+	for _, l := range literals {
+		c.Insert(l)
+	}
+	return *c
+}
@@ -0,0 +1,101 @@
+package tracecheck
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+)
+
+func TestWrite(t *testing.T) {
+	set := clausetest.ParseSet(t, "A,B", "-A,B", "-B")
+	unsat, steps := clause.Refute(set, nil)
+	if !unsat {
+		t.Fatalf("Refute() = false; want true")
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, FromRefutation(set, steps)); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	expected := "1 1 2 0 0\n2 -1 2 0 0\n3 -2 0 0\n4 2 0 1 2 0\n5 0 3 4 0\n"
+	if buf.String() != expected {
+		t.Errorf("Write() = %q; want %q", buf.String(), expected)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	// Refutations of random clause sets survive writing, reading and checking
+	r := rand.New(rand.NewSource(5))
+	replayed := 0
+	for n := 0; n < 200; n++ {
+		set := clausetest.RandomSet(r, 1+r.Intn(4), 2+r.Intn(7))
+		unsat, steps := clause.Refute(set, nil)
+		if !unsat {
+			continue
+		}
+		replayed++
+		var buf bytes.Buffer
+		if err := Write(&buf, FromRefutation(set, steps)); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+		lines, err := Read(&buf)
+		if err != nil {
+			t.Fatalf("Read() failed: %v", err)
+		}
+		if err := Check(set, lines); err != nil {
+			t.Errorf("Check() = %v for clauses %v", err, set)
+		}
+	}
+	if replayed == 0 {
+		t.Fatalf("no unsatisfiable clause sets generated")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	set := clausetest.ParseSet(t, "A,B", "-A,B", "A,-B", "-A,-B")
+	inputs := "1 1 2 0 0\n2 -1 2 0 0\n3 1 -2 0 0\n4 -1 -2 0 0\n"
+	tests := []struct {
+		name     string
+		trace    string
+		expected string
+	}{
+		{"valid", inputs + "5 2 0 1 2 0\n6 -2 0 3 4 0\n7 0 5 6 0\n", ""},
+		{"chain", inputs + "5 2 0 1 2 0\n6 0 3 4 5 0\n", ""},
+		{"not an input", "1 1 0 0\n", "clause 1: {A} is not an input clause"},
+		{"defined twice", inputs + "4 2 0 1 2 0\n", "clause 4: defined twice"},
+		{"one antecedent", inputs + "5 1 2 0 1 0\n", "clause 5: a derived clause needs at least two antecedents"},
+		{"undefined antecedent", inputs + "5 2 0 1 9 0\n", "clause 5: antecedent 9 is not defined before"},
+		{"no clash", inputs + "5 1 0 1 1 0\n", "clause 5: antecedent 1 does not clash with {A, B} on exactly one literal"},
+		{"wrong resolvent", inputs + "5 1 2 0 1 2 0\n", "clause 5: resolving the antecedents gives {B}, not {A, B}"},
+		{"no empty clause", inputs + "5 2 0 1 2 0\n", "the trace does not derive the empty clause"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := Read(strings.NewReader(tt.trace))
+			if err != nil {
+				t.Fatalf("Read() failed: %v", err)
+			}
+			err = Check(set, lines)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Check() = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Check() = %v; want %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, input := range []string{"1 x 0 0\n", "1 1 2\n", "1 1 0 2\n"} {
+		if _, err := Read(strings.NewReader(input)); err == nil {
+			t.Errorf("Read(%q) succeeded; want error", input)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package trail holds a partial assignment together with the order in which its literals were
+// assigned, and extends it by unit propagation. It is the common core of the search engines
+// and of the checks that propagate units.
+package trail
+
+import (
+	"sort"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+// Trail is a partial assignment that can be undone in reverse assignment order.
+type Trail struct {
+	// assignment maps every assigned variable to its value
+	assignment map[clause.Literal]bool
+	// reason maps every propagated variable to the index of the clause that propagated it
+	reason map[clause.Literal]int
+	// literals lists the assigned literals in assignment order
+	literals []clause.Literal
+}
+
+// New creates an empty trail.
+func New() *Trail {
+	return &Trail{assignment: map[clause.Literal]bool{}, reason: map[clause.Literal]int{}}
+}
+
+// Value returns the value of a literal and whether its variable is assigned.
+func (t *Trail) Value(l clause.Literal) (bool, bool) {
+	value, ok := t.assignment[l.Var()]
+	return value == (l > 0), ok
+}
+
+// Assigned checks if the variable of the literal is assigned.
+func (t *Trail) Assigned(l clause.Literal) bool {
+	_, ok := t.assignment[l.Var()]
+	return ok
+}
+
+// Assign makes the literal true without a reason, as a decision or an assumption.
+func (t *Trail) Assign(l clause.Literal) {
+	t.assignment[l.Var()] = l > 0
+	t.literals = append(t.literals, l)
+}
+
+// Reason returns the index of the clause that propagated the variable of the literal,
+// and false if the variable is unassigned or was assigned without a reason.
+func (t *Trail) Reason(l clause.Literal) (int, bool) {
+	r, ok := t.reason[l.Var()]
+	return r, ok
+}
+
+// Literals returns the assigned literals in assignment order. The slice must not be modified.
+func (t *Trail) Literals() []clause.Literal {
+	return t.literals
+}
+
+// Len returns the number of assigned literals, a mark Undo can return to.
+func (t *Trail) Len() int {
+	return len(t.literals)
+}
+
+// Undo removes every assignment made after the trail had the given length.
+func (t *Trail) Undo(mark int) {
+	for _, l := range t.literals[mark:] {
+		delete(t.assignment, l.Var())
+		delete(t.reason, l.Var())
+	}
+	t.literals = t.literals[:mark]
+}
+
+// Pick returns the first unassigned variable of the order, or ErrorLiteral if every variable is assigned.
+func (t *Trail) Pick(order []clause.Literal) clause.Literal {
+	for _, v := range order {
+		if !t.Assigned(v) {
+			return v.Var()
+		}
+	}
+	return clause.ErrorLiteral
+}
+
+// Propagate assigns the remaining literal of every unit clause until no unit clause is left,
+// recording the index of the clause as the reason of the literal.
+// Returns the index of a falsified clause, or -1 if there is none.
+// If st is not nil, propagated literals and falsified clauses are recorded.
+func (t *Trail) Propagate(clauses [][]clause.Literal, st *stats.Stats) int {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	for changed := true; changed; {
+		changed = false
+		for i, c := range clauses {
+			unassigned := clause.ErrorLiteral
+			count := 0
+			satisfied := false
+			for _, l := range c {
+				value, ok := t.Value(l)
+				if !ok {
+					unassigned = l
+					count++
+				} else if value {
+					satisfied = true
+					break
+				}
+			}
+			if satisfied {
+				continue
+			}
+			if count == 0 {
+				st.Conflicts++
+				return i
+			}
+			if count == 1 {
+				st.Propagations++
+				t.Assign(unassigned)
+				t.reason[unassigned.Var()] = i
+				changed = true
+			}
+		}
+	}
+	return -1
+}
+
+// Model returns the assigned literals sorted by variable.
+func (t *Trail) Model() []clause.Literal {
+	model := append([]clause.Literal{}, t.literals...)
+	sort.Slice(model, func(i, k int) bool { return model[i].Var() < model[k].Var() })
+	return model
+}
@@ -0,0 +1,70 @@
+package trail
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+func TestUndo(t *testing.T) {
+	tr := New()
+	tr.Assign(1)
+	tr.Assign(-2)
+	tr.Assign(3)
+	tr.Undo(1)
+	if tr.Len() != 1 || !reflect.DeepEqual(tr.Literals(), []clause.Literal{1}) {
+		t.Errorf("Undo(1) left %v", tr.Literals())
+	}
+	if value, ok := tr.Value(1); !ok || !value {
+		t.Errorf("Value(A) = %v, %v; want true, true", value, ok)
+	}
+	if _, ok := tr.Value(-2); ok {
+		t.Errorf("Value(-B) is assigned after Undo(1)")
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	clauses := [][]clause.Literal{{-1, 2}, {-2, 3}, {1, 4}}
+	tr := New()
+	tr.Assign(1)
+	st := &stats.Stats{}
+	if conflict := tr.Propagate(clauses, st); conflict != -1 {
+		t.Fatalf("Propagate() = %d; want -1", conflict)
+	}
+	if !reflect.DeepEqual(tr.Literals(), []clause.Literal{1, 2, 3}) {
+		t.Errorf("Propagate() assigned %v; want [1 2 3]", tr.Literals())
+	}
+	if r, ok := tr.Reason(3); !ok || r != 1 {
+		t.Errorf("Reason(C) = %d, %v; want 1, true", r, ok)
+	}
+	if _, ok := tr.Reason(1); ok {
+		t.Errorf("Reason(A) of an assigned literal exists")
+	}
+	if st.Propagations != 2 {
+		t.Errorf("Propagations = %d; want 2", st.Propagations)
+	}
+	if v := tr.Pick([]clause.Literal{1, 2, 3, 4}); v != 4 {
+		t.Errorf("Pick() = %d; want 4", v)
+	}
+	tr.Assign(-4)
+	if v := tr.Pick([]clause.Literal{1, 2, 3, 4}); v != clause.ErrorLiteral {
+		t.Errorf("Pick() = %d; want ErrorLiteral", v)
+	}
+	if model := tr.Model(); !reflect.DeepEqual(model, []clause.Literal{1, 2, 3, -4}) {
+		t.Errorf("Model() = %v; want [1 2 3 -4]", model)
+	}
+}
+
+func TestPropagateConflict(t *testing.T) {
+	tr := New()
+	tr.Assign(1)
+	st := &stats.Stats{}
+	if conflict := tr.Propagate([][]clause.Literal{{-1, 2}, {-1, -2}}, st); conflict != 1 {
+		t.Errorf("Propagate() = %d; want 1", conflict)
+	}
+	if st.Conflicts != 1 {
+		t.Errorf("Conflicts = %d; want 1", st.Conflicts)
+	}
+}
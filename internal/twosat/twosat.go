@@ -0,0 +1,198 @@
+// Package twosat provides a linear-time decision procedure for clause sets
+// in which every clause contains at most two literals.
+package twosat
+
+import (
+	"strings"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+// Conflict explains why a 2-CNF clause set is unsatisfiable.
+// Literal implies its own negation and the negation implies Literal,
+// so neither value can be assigned to its variable.
+type Conflict struct {
+	// Literal is the literal that lies in the same strongly connected component as its negation.
+	Literal clause.Literal
+	// Forward is the implication path from Literal to its negation.
+	Forward []clause.Literal
+	// Backward is the implication path from the negation back to Literal.
+	Backward []clause.Literal
+}
+
+// String returns both implication paths of the conflict.
+// Example: "A => B => -A, -A => A".
+func (c *Conflict) String() string {
+	return formatPath(c.Forward) + ", " + formatPath(c.Backward)
+}
+
+// IsTwoCNF checks if every clause of the set contains at most two literals.
+func IsTwoCNF(set []clause.Clause) bool {
+	for i := range set {
+		if set[i].Size() > 2 {
+			return false
+		}
+	}
+	return true
+}
+
+// Solve decides a 2-CNF clause set using the strongly connected components of its implication graph.
+// Every clause (a ∨ b) contributes the implications ¬a ⇒ b and ¬b ⇒ a, a unit clause (a) contributes ¬a ⇒ a.
+// The clause set is unsatisfiable if and only if some literal and its negation share a component.
+//
+// Returns true if the clause set is unsatisfiable, together with a conflict explaining it
+// (nil if the set contains the empty clause). Otherwise it returns false and a model,
+// given as the true literals sorted by variable.
+//
+// The clause set must be 2-CNF (see IsTwoCNF).
+// If st is not nil, every literal assigned from the components is recorded as a propagation.
+func Solve(set []clause.Clause, st *stats.Stats) (bool, []clause.Literal, *Conflict) {
+	if st == nil {
+		st = &stats.Stats{}
+	}
+	for i := range set {
+		if set[i].IsEmpty() {
+			return true, nil, nil
+		}
+	}
+	g := newGraph(set)
+	vars := clause.Variables(set)
+	component := g.components(vars)
+	for _, v := range vars {
+		if component[v] == component[-v] {
+			return true, nil, &Conflict{
+				Literal:  v,
+				Forward:  g.path(v, -v),
+				Backward: g.path(-v, v),
+			}
+		}
+	}
+	// Components are numbered in reverse topological order,
+	// so a literal is true if its component comes before the one of its negation
+	model := make([]clause.Literal, len(vars))
+	for i, v := range vars {
+		if component[v] < component[-v] {
+			model[i] = v
+		} else {
+			model[i] = -v
+		}
+		st.Propagations++
+	}
+	return false, model, nil
+}
+
+// Components computes the strongly connected components of the implication graph of the clauses
+// with one or two literals; longer clauses are ignored. It maps every literal of the variables of
+// the set to the index of its component. Literals with the same index imply each other.
+func Components(set []clause.Clause) map[clause.Literal]int {
+	return newGraph(set).components(clause.Variables(set))
+}
+
+// graph is the implication graph of a 2-CNF clause set.
+type graph struct {
+	edges map[clause.Literal][]clause.Literal
+}
+
+// newGraph returns the implication graph of the clauses with one or two literals.
+func newGraph(set []clause.Clause) *graph {
+	g := &graph{edges: map[clause.Literal][]clause.Literal{}}
+	for i := range set {
+		literals := set[i].Literals()
+		switch len(literals) {
+		case 1:
+			g.add(-literals[0], literals[0])
+		case 2:
+			g.add(-literals[0], literals[1])
+			g.add(-literals[1], literals[0])
+		}
+	}
+	return g
+}
+
+// add inserts the implication from ⇒ to.
+func (g *graph) add(from, to clause.Literal) {
+	g.edges[from] = append(g.edges[from], to)
+}
+
+// components computes the strongly connected components with Tarjan's algorithm.
+// It maps every literal of the given variables to the index of its component.
+func (g *graph) components(vars []clause.Literal) map[clause.Literal]int {
+	index := map[clause.Literal]int{}
+	low := map[clause.Literal]int{}
+	onStack := map[clause.Literal]bool{}
+	stack := []clause.Literal{}
+	component := map[clause.Literal]int{}
+	count := 0
+	var visit func(l clause.Literal)
+	visit = func(l clause.Literal) {
+		index[l] = len(index)
+		low[l] = index[l]
+		stack = append(stack, l)
+		onStack[l] = true
+		for _, next := range g.edges[l] {
+			if _, ok := index[next]; !ok {
+				visit(next)
+				low[l] = min(low[l], low[next])
+			} else if onStack[next] {
+				low[l] = min(low[l], index[next])
+			}
+		}
+		if low[l] != index[l] {
+			return
+		}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component[top] = count
+			if top == l {
+				break
+			}
+		}
+		count++
+	}
+	for _, v := range vars {
+		for _, l := range []clause.Literal{v, -v} {
+			if _, ok := index[l]; !ok {
+				visit(l)
+			}
+		}
+	}
+	return component
+}
+
+// path returns the shortest implication path from one literal to another using breadth-first search.
+// The path starts with from and ends with to. Returns nil if to is not reachable.
+func (g *graph) path(from, to clause.Literal) []clause.Literal {
+	parent := map[clause.Literal]clause.Literal{from: clause.ErrorLiteral}
+	queue := []clause.Literal{from}
+	for len(queue) > 0 {
+		l := queue[0]
+		queue = queue[1:]
+		for _, next := range g.edges[l] {
+			if _, ok := parent[next]; ok {
+				continue
+			}
+			parent[next] = l
+			if next == to {
+				path := []clause.Literal{}
+				for p := to; p != clause.ErrorLiteral; p = parent[p] {
+					path = append([]clause.Literal{p}, path...)
+				}
+				return path
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+// formatPath joins the literals of an implication path with arrows.
+func formatPath(path []clause.Literal) string {
+	s := make([]string, len(path))
+	for i, l := range path {
+		s[i] = clause.Lit2Str(l)
+	}
+	return strings.Join(s, " => ")
+}
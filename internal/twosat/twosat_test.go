@@ -0,0 +1,127 @@
+package twosat
+
+import (
+	"testing"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/clausetest"
+)
+
+func TestIsTwoCNF(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		expected bool
+	}{
+		{"empty clause set", []string{}, true},
+		{"units", []string{"A", "-B"}, true},
+		{"binary clauses", []string{"A,B", "-A,-C"}, true},
+		{"ternary clause", []string{"A,B", "A,B,C"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsTwoCNF(clausetest.ParseSet(t, tt.clauses...))
+			if result != tt.expected {
+				t.Errorf("IsTwoCNF(%v) = %v; want %v", tt.clauses, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		clauses  []string
+		expected bool
+	}{
+		{"empty clause set", []string{}, false},
+		{"single unit", []string{"-A"}, false},
+		{"simple contradiction", []string{"A", "-A"}, true},
+		{"all four binary clauses", []string{"A,B", "-A,B", "A,-B", "-A,-B"}, true},
+		{"satisfiable chain", []string{"-A,B", "-B,C", "-C,D"}, false},
+		{"chain with units", []string{"A", "-A,B", "-B,C", "-C"}, true},
+		{"cycle", []string{"-A,B", "-B,C", "-C,A", "A,B"}, false},
+		{"equivalence forced false", []string{"-A,B", "-B,A", "-A,-B", "A,C", "-C,B"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := clausetest.ParseSet(t, tt.clauses...)
+			result, model, conflict := Solve(set, nil)
+			if result != tt.expected {
+				t.Fatalf("Solve(%v) = %v; want %v", tt.clauses, result, tt.expected)
+			}
+			if expected := clause.Res(clausetest.ParseSet(t, tt.clauses...), 0); result != expected {
+				t.Errorf("Solve(%v) = %v; clause.Res = %v", tt.clauses, result, expected)
+			}
+			if result {
+				checkConflict(t, set, conflict)
+				return
+			}
+			if len(model) != len(clause.Variables(set)) {
+				t.Errorf("Solve(%v) model = %v does not assign every variable", tt.clauses, model)
+			}
+			for i := range set {
+				satisfied := false
+				for _, l := range model {
+					satisfied = satisfied || set[i].Contains(l)
+				}
+				if !satisfied {
+					t.Errorf("Solve(%v) model = %v falsifies %s", tt.clauses, model, set[i].String())
+				}
+			}
+		})
+	}
+}
+
+func TestSolveEmptyClause(t *testing.T) {
+	result, model, conflict := Solve([]clause.Clause{*clause.New()}, nil)
+	if !result || model != nil || conflict != nil {
+		t.Errorf("Solve() = %v, %v, %v; want true, nil, nil", result, model, conflict)
+	}
+}
+
+func TestConflictString(t *testing.T) {
+	set := clausetest.ParseSet(t, "A", "-A,B", "-B")
+	_, _, conflict := Solve(set, nil)
+	if conflict == nil {
+		t.Fatalf("Solve() returned no conflict")
+	}
+	expected := "A => B => -B => -A, -A => A"
+	if conflict.String() != expected {
+		t.Errorf("Conflict.String() = %q; want %q", conflict.String(), expected)
+	}
+}
+
+// Helper function to check that every step of both conflict paths is an implication of the clause set
+func checkConflict(t *testing.T, set []clause.Clause, conflict *Conflict) {
+	t.Helper()
+	if conflict == nil {
+		t.Fatalf("unsatisfiable set without conflict")
+	}
+	x := conflict.Literal
+	for _, path := range [][]clause.Literal{conflict.Forward, conflict.Backward} {
+		if len(path) < 2 {
+			t.Fatalf("path %v is too short", path)
+		}
+		for i := 0; i+1 < len(path); i++ {
+			implied := false
+			for k := range set {
+				if path[i+1] == -path[i] {
+					// A unit clause (q) contributes the implication -q => q
+					implied = implied || (set[k].Size() == 1 && set[k].Contains(path[i+1]))
+				} else {
+					implied = implied || (set[k].Contains(-path[i]) && set[k].Contains(path[i+1]))
+				}
+			}
+			if !implied {
+				t.Errorf("%s => %s is not an implication of the clause set", clause.Lit2Str(path[i]), clause.Lit2Str(path[i+1]))
+			}
+		}
+	}
+	if conflict.Forward[0] != x || conflict.Forward[len(conflict.Forward)-1] != -x {
+		t.Errorf("Forward = %v; want a path from %d to %d", conflict.Forward, x, -x)
+	}
+	if conflict.Backward[0] != -x || conflict.Backward[len(conflict.Backward)-1] != x {
+		t.Errorf("Backward = %v; want a path from %d to %d", conflict.Backward, -x, x)
+	}
+}
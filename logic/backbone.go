@@ -0,0 +1,48 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"github.com/thxrsxm/res/internal/backbone"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+// BackboneResult is the backbone of a clause set.
+type BackboneResult struct {
+	// Verdict tells if the clause set is satisfiable. An unsatisfiable set has no backbone.
+	Verdict Verdict
+	// Forced lists the literals that are true in every model, sorted by variable.
+	Forced []Literal
+	// Unforced lists the variables that are true in some models and false in others, sorted.
+	// Their values may still depend on each other, e.g. A,B and -A,-B force neither A nor B but make them differ.
+	Unforced []Literal
+	// Queries is the number of incremental queries the computation took.
+	Queries int
+	// Stats describes the work of the incremental solver over all queries.
+	Stats Stats
+}
+
+// Backbone computes the literals that are true in every model of the clauses. It asks an incremental
+// solver at most one query per variable under assumptions, instead of enumerating the models.
+// It returns ctx.Err() if the context is done before the backbone is known.
+func Backbone(ctx context.Context, clauses []Clause) (BackboneResult, error) {
+	st := stats.Stats{}
+	start := time.Now()
+	r, err := backbone.Compute(ctx, toClauses(clauses), &st)
+	st.Elapsed = time.Since(start)
+	if err != nil {
+		return BackboneResult{}, err
+	}
+	result := BackboneResult{
+		Verdict:  Satisfiable,
+		Forced:   fromLiterals(r.Forced),
+		Unforced: fromLiterals(r.Unforced),
+		Queries:  r.Queries,
+		Stats:    fromStats(st),
+	}
+	if r.Unsatisfiable {
+		result.Verdict = Unsatisfiable
+	}
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package logic
+
+import (
+	"fmt"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+// Literal is a variable (1 is A, 26 is Z) or, if negative, its negation.
+type Literal int
+
+// MaxVariable is the largest variable that can be represented (Z).
+const MaxVariable = Literal(clause.MaxVariable)
+
+// Var returns the variable of the literal, i.e. the literal without its sign.
+func (l Literal) Var() Literal {
+	if l < 0 {
+		return -l
+	}
+	return l
+}
+
+// String returns the name of the literal, e.g. "A" or "-B".
+func (l Literal) String() string {
+	return clause.Lit2Str(clause.Literal(l))
+}
+
+// Clause is a disjunction of literals. Adding the complement of a contained literal removes both,
+// so a tautology becomes the empty clause. The zero value is the empty clause. A Clause is
+// never modified after it is created, so it can be shared freely.
+type Clause struct {
+	// literals is sorted by variable
+	literals []Literal
+}
+
+// Literals returns the literals of the clause sorted by variable.
+func (c Clause) Literals() []Literal {
+	return append([]Literal{}, c.literals...)
+}
+
+// Contains checks if the clause contains the literal.
+func (c Clause) Contains(l Literal) bool {
+	for _, k := range c.literals {
+		if k == l {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the number of literals of the clause.
+func (c Clause) Size() int {
+	return len(c.literals)
+}
+
+// IsEmpty checks if the clause has no literals. The empty clause is always false.
+func (c Clause) IsEmpty() bool {
+	return len(c.literals) == 0
+}
+
+// String returns the clause in set notation, e.g. "{-A, B, C}".
+func (c Clause) String() string {
+	internal := c.internal()
+	return internal.String()
+}
+
+// Parse parses a clause in the format A,B,-C.
+func Parse(s string) (*Clause, error) {
+	c, err := clause.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	result := fromClause(*c)
+	return &result, nil
+}
+
+// ParseClauses parses every argument as a clause.
+func ParseClauses(s ...string) ([]Clause, error) {
+	set := make([]Clause, 0, len(s))
+	for _, arg := range s {
+		c, err := clause.Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("parsing clause %q: %v", arg, err)
+		}
+		set = append(set, fromClause(*c))
+	}
+	return set, nil
+}
+
+// NewClause returns a clause containing the given literals.
+// It returns an error if a literal is not a valid variable or its negation.
+func NewClause(literals ...Literal) (Clause, error) {
+	c := clause.New()
+	for _, l := range literals {
+		if l == 0 || l.Var() > MaxVariable {
+			return Clause{}, fmt.Errorf("invalid literal %d", l)
+		}
+		c.Insert(clause.Literal(l))
+	}
+	return fromClause(*c), nil
+}
+
+// internal converts the clause to a clause of the internal packages.
+func (c Clause) internal() clause.Clause {
+	result := clause.New()
+	for _, l := range c.literals {
+		result.Insert(clause.Literal(l))
+	}
+	return *result
+}
+
+// fromClause converts a clause of the internal packages.
+func fromClause(c clause.Clause) Clause {
+	return Clause{literals: fromLiterals(c.Literals())}
+}
+
+// toClauses converts clauses to clauses of the internal packages.
+func toClauses(clauses []Clause) []clause.Clause {
+	set := make([]clause.Clause, len(clauses))
+	for i := range clauses {
+		set[i] = clauses[i].internal()
+	}
+	return set
+}
+
+// fromLiterals converts literals of the internal packages, keeping nil.
+func fromLiterals(literals []clause.Literal) []Literal {
+	if literals == nil {
+		return nil
+	}
+	result := make([]Literal, len(literals))
+	for i, l := range literals {
+		result[i] = Literal(l)
+	}
+	return result
+}
+
+// toLiterals converts literals to literals of the internal packages.
+func toLiterals(literals []Literal) []clause.Literal {
+	result := make([]clause.Literal, len(literals))
+	for i, l := range literals {
+		result[i] = clause.Literal(l)
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package logic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/thxrsxm/res/internal/incremental"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+// EngineIncremental is the name of the engine of Incremental.
+const EngineIncremental = "incremental"
+
+// Incremental decides a clause set that grows between queries, each under its own assumptions.
+// Clauses learnt by a query follow from the clause set alone and are kept for later queries.
+// Clauses can be added in nested scopes, which Pop retracts together with the clauses learnt from them.
+// An Incremental may be used by several goroutines at once; queries run one after another.
+type Incremental struct {
+	mu     sync.Mutex
+	solver *incremental.Solver
+}
+
+// NewIncremental returns an incremental solver without clauses.
+func NewIncremental() *Incremental {
+	return &Incremental{solver: incremental.New()}
+}
+
+// Add adds clauses for every later query.
+func (s *Incremental) Add(clauses ...Clause) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.solver.Add(toClauses(clauses)...)
+}
+
+// Push opens a scope. Clauses added until the matching Pop are retracted by it.
+func (s *Incremental) Push() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.solver.Push()
+}
+
+// Pop closes the innermost scope. It retracts the clauses added since the matching Push and every
+// learnt clause that depends on them. It returns an error if no scope is open.
+func (s *Incremental) Pop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.solver.Pop()
+}
+
+// Solve decides the clauses under the assumptions, literals that are true for this query only.
+// If the result is unsatisfiable, Failed lists the assumptions responsible for it, or is empty
+// if the clauses are unsatisfiable on their own. It returns ctx.Err() if the context is done
+// before the answer is found.
+func (s *Incremental) Solve(ctx context.Context, assumptions []Literal) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := stats.Stats{}
+	start := time.Now()
+	r, err := s.solver.Solve(ctx, toLiterals(assumptions), &st)
+	st.Elapsed = time.Since(start)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Verdict: Satisfiable, Model: fromLiterals(r.Model), Stats: fromStats(st), Engine: EngineIncremental}
+	if r.Unsatisfiable {
+		result.Verdict = Unsatisfiable
+		result.Failed = fromLiterals(r.Failed)
+	}
+	return result, nil
+}
@@ -0,0 +1,224 @@
+// Package logic is the supported Go API of res: it parses propositional clauses and decides
+// their satisfiability with the engines of the res command.
+//
+// # Compatibility
+//
+// The package follows semantic versioning, and Version names the API version it implements.
+// Within a major version, exported identifiers are neither removed nor changed in an incompatible
+// way; new identifiers, engines and fields of Result and Stats may be added. Everything below
+// internal/ may change at any time and is not covered by this promise.
+//
+// # Clauses
+//
+// A literal is a variable from A to Z, numbered from 1 to 26, and is negative if the variable
+// is negated. This is also the numbering of the DIMACS format. A clause is a set of literals:
+//
+//	c, err := logic.Parse("A,-B")
+//
+// Clauses, literals, statistics and proofs are types of this package. The engines below internal/
+// work on their own representation, which results are converted from, so it can change without
+// affecting the API.
+//
+// # Solving
+//
+//	s, err := logic.NewSolver(logic.EngineAuto)
+//	result, err := s.Solve(ctx, clauses)
+//	if result.Verdict == logic.Unsatisfiable {
+//		...
+//	}
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	"time"
+
+	"github.com/thxrsxm/res/internal/solver"
+	"github.com/thxrsxm/res/internal/stats"
+)
+
+// Version is the version of the API.
+const Version = "1.0.0"
+
+// Names of the engines that can decide a clause set.
+const (
+	EngineAuto          = solver.EngineAuto
+	EngineHorn          = solver.EngineHorn
+	EngineTwoSAT        = solver.EngineTwoSAT
+	EngineRenamableHorn = solver.EngineRenamableHorn
+	EngineResolution    = solver.EngineResolution
+	EngineDP            = solver.EngineDP
+	EngineDPLL          = solver.EngineDPLL
+	EngineBruteForce    = solver.EngineBruteForce
+	// EnginePortfolio runs several engines concurrently and reports the first answer.
+	// The Engine of its results names the engine that answered, e.g. "portfolio/dpll".
+	EnginePortfolio = solver.EnginePortfolio
+)
+
+// Verdict is the answer of a solver.
+type Verdict int
+
+const (
+	// Unknown means the solver gave no answer.
+	Unknown Verdict = iota
+	// Satisfiable means some assignment satisfies every clause.
+	Satisfiable
+	// Unsatisfiable means no assignment satisfies every clause.
+	Unsatisfiable
+)
+
+// String returns the name of the verdict.
+func (v Verdict) String() string {
+	switch v {
+	case Satisfiable:
+		return "satisfiable"
+	case Unsatisfiable:
+		return "unsatisfiable"
+	default:
+		return "unknown"
+	}
+}
+
+// Result is the outcome of solving a clause set.
+type Result struct {
+	// Verdict tells if the clause set is satisfiable.
+	Verdict Verdict
+	// Model lists the true literals of a satisfying assignment. Variables that are not listed
+	// can take either value. It is nil if the set is unsatisfiable or the engine does not produce models.
+	Model []Literal
+	// Proof is a DRAT proof of an unsatisfiable clause set.
+	// It is nil if the set is satisfiable or the engine does not produce proofs.
+	Proof *Proof
+	// Failed lists the assumptions that are unsatisfiable together with the clauses.
+	// It is only set by Incremental.Solve.
+	Failed []Literal
+	// Stats describes the work of the engine.
+	Stats Stats
+	// Engine is the name of the engine that decided the clause set.
+	Engine string
+}
+
+// Stats describes the work of an engine. Every engine fills the counters that apply to it
+// and leaves the others at zero.
+type Stats struct {
+	// Rounds is the number of saturation rounds or eliminated variables.
+	Rounds int `json:"rounds"`
+	// Resolvents is the number of resolution steps that produced a new clause.
+	Resolvents int `json:"resolvents"`
+	// Duplicates is the number of resolvents discarded because they were already known.
+	Duplicates int `json:"duplicates"`
+	// Tautologies is the number of resolutions discarded because the resolvent would be a tautology.
+	Tautologies int `json:"tautologies"`
+	// Subsumptions is the number of clauses removed because another clause subsumes them.
+	Subsumptions int `json:"subsumptions"`
+	// Decisions is the number of branching decisions.
+	Decisions int `json:"decisions"`
+	// Conflicts is the number of falsified clauses found during search.
+	Conflicts int `json:"conflicts"`
+	// Propagations is the number of literals assigned by propagation.
+	Propagations int `json:"propagations"`
+	// PeakClauses is the largest number of clauses held at the same time.
+	PeakClauses int `json:"peak_clauses"`
+	// MaxClauseSize is the number of literals of the largest clause seen.
+	MaxClauseSize int `json:"max_clause_size"`
+	// Elapsed is the time the engine took.
+	Elapsed time.Duration `json:"elapsed_ns"`
+}
+
+// String returns the counters as aligned text, one per line.
+func (s *Stats) String() string {
+	st := s.internal()
+	return st.String()
+}
+
+// internal converts the counters to the counters of the internal packages.
+func (s *Stats) internal() stats.Stats {
+	return stats.Stats{
+		Rounds:        s.Rounds,
+		Resolvents:    s.Resolvents,
+		Duplicates:    s.Duplicates,
+		Tautologies:   s.Tautologies,
+		Subsumptions:  s.Subsumptions,
+		Decisions:     s.Decisions,
+		Conflicts:     s.Conflicts,
+		Propagations:  s.Propagations,
+		PeakClauses:   s.PeakClauses,
+		MaxClauseSize: s.MaxClauseSize,
+		Elapsed:       s.Elapsed,
+	}
+}
+
+// fromStats converts counters of the internal packages. Counters the public type does not
+// have are dropped, so new internal counters do not change this package.
+func fromStats(st stats.Stats) Stats {
+	return Stats{
+		Rounds:        st.Rounds,
+		Resolvents:    st.Resolvents,
+		Duplicates:    st.Duplicates,
+		Tautologies:   st.Tautologies,
+		Subsumptions:  st.Subsumptions,
+		Decisions:     st.Decisions,
+		Conflicts:     st.Conflicts,
+		Propagations:  st.Propagations,
+		PeakClauses:   st.PeakClauses,
+		MaxClauseSize: st.MaxClauseSize,
+		Elapsed:       st.Elapsed,
+	}
+}
+
+// Solver decides the satisfiability of clause sets. A Solver may be used by several goroutines at once.
+type Solver interface {
+	// Solve decides a clause set. It returns ctx.Err() if the context is done before the answer is found,
+	// and an error if the engine cannot decide the clause set.
+	Solve(ctx context.Context, clauses []Clause) (Result, error)
+}
+
+// Engines returns the names of the available engines, starting with EngineAuto.
+func Engines() []string {
+	names := []string{}
+	for _, e := range solver.Engines() {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// NewSolver returns a solver using the named engine. EngineAuto picks the most specific engine for
+// every clause set. The horn, 2-sat and renamable-horn engines only decide clause sets of their class.
+func NewSolver(engine string) (Solver, error) {
+	e, ok := solver.Lookup(engine)
+	if !ok {
+		return nil, fmt.Errorf("unknown engine: %q", engine)
+	}
+	return engineSolver{e}, nil
+}
+
+// engineSolver adapts an engine of the solver package to the Solver interface.
+type engineSolver struct {
+	engine solver.Engine
+}
+
+// Solve runs the engine on the clauses converted to the internal representation. The engines stop when the context is done,
+// except for the linear-time horn, 2-sat and renamable-horn engines, which run to the end.
+func (s engineSolver) Solve(ctx context.Context, clauses []Clause) (Result, error) {
+	r, err := solver.SolveEngine(ctx, s.engine.Name, toClauses(clauses), solver.Options{})
+	if err != nil {
+		return Result{}, err
+	}
+	return convert(r), nil
+}
+
+// convert converts a result of the solver package.
+func convert(r solver.Result) Result {
+	result := Result{
+		Verdict: Satisfiable,
+		Model:   fromLiterals(r.Model),
+		Proof:   fromProof(r.Proof),
+		Stats:   fromStats(r.Stats),
+		Engine:  r.Engine,
+	}
+	if r.Unsatisfiable {
+		result.Verdict = Unsatisfiable
+	}
+	return result
+}
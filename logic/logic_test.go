@@ -0,0 +1,256 @@
+package logic_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thxrsxm/res/logic"
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		clauses []string
+		verdict logic.Verdict
+	}{
+		{"empty clause set", []string{}, logic.Satisfiable},
+		{"satisfiable", []string{"A,B,C", "-A,-B,-C", "A,-B", "-A,C"}, logic.Satisfiable},
+		{"unsatisfiable", []string{"A,B,C", "A,B,-C", "A,-B", "-A,B", "-A,-B"}, logic.Unsatisfiable},
+		{"empty clause", []string{"A", "B,-B"}, logic.Unsatisfiable},
+	}
+	specialised := map[string]bool{logic.EngineHorn: true, logic.EngineTwoSAT: true, logic.EngineRenamableHorn: true}
+	for _, tt := range tests {
+		for _, engine := range logic.Engines() {
+			t.Run(tt.name+"/"+engine, func(t *testing.T) {
+				set, err := logic.ParseClauses(tt.clauses...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				s, err := logic.NewSolver(engine)
+				if err != nil {
+					t.Fatal(err)
+				}
+				result, err := s.Solve(context.Background(), set)
+				if err != nil {
+					// The specialised engines reject clause sets outside their class
+					if specialised[engine] {
+						t.Skipf("%s: %v", engine, err)
+					}
+					t.Fatalf("Solve(%v) failed: %v", tt.clauses, err)
+				}
+				if result.Verdict != tt.verdict {
+					t.Errorf("Solve(%v).Verdict = %v; want %v", tt.clauses, result.Verdict, tt.verdict)
+				}
+				if result.Verdict == logic.Unsatisfiable && result.Model != nil {
+					t.Errorf("Solve(%v).Model = %v; want nil", tt.clauses, result.Model)
+				}
+				if result.Verdict == logic.Satisfiable && result.Proof != nil {
+					t.Errorf("Solve(%v).Proof = %v; want nil", tt.clauses, result.Proof)
+				}
+				if result.Engine == "" {
+					t.Errorf("Solve(%v).Engine is empty", tt.clauses)
+				}
+			})
+		}
+	}
+}
+
+func TestSolveOutsideClass(t *testing.T) {
+	set, err := logic.ParseClauses("A,B,C", "-A,-B,-C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := logic.NewSolver(logic.EngineHorn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Solve(context.Background(), set); err == nil {
+		t.Errorf("horn engine solved a non-Horn clause set without error")
+	}
+}
+
+func TestSolveCancelled(t *testing.T) {
+	s, err := logic.NewSolver(logic.EngineAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Solve(ctx, nil); err != context.Canceled {
+		t.Errorf("Solve with a cancelled context returned %v; want %v", err, context.Canceled)
+	}
+}
+
+func TestSolveCancelledRunning(t *testing.T) {
+	// A and -A make the set unsatisfiable, so brute force tries all 2^26 assignments
+	literals := []logic.Literal{}
+	for v := logic.Literal(2); v <= logic.MaxVariable; v++ {
+		literals = append(literals, v)
+	}
+	long, err := logic.NewClause(literals...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := logic.ParseClauses("A", "-A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	set = append(set, long)
+	s, err := logic.NewSolver(logic.EngineBruteForce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.Solve(ctx, set); err != context.DeadlineExceeded {
+		t.Fatalf("Solve after the deadline returned %v; want %v", err, context.DeadlineExceeded)
+	}
+	// The search must stop with Solve instead of running on in the background
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("%d goroutines after Solve returned; want at most %d", after, before)
+	}
+}
+
+func TestNewSolverUnknownEngine(t *testing.T) {
+	if _, err := logic.NewSolver("magic"); err == nil {
+		t.Errorf("NewSolver(%q) returned no error", "magic")
+	}
+}
+
+func TestNewClause(t *testing.T) {
+	c, err := logic.NewClause(1, -2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.String() != "{A}" {
+		t.Errorf("NewClause(1, -2, 2) = %s; want {A}", c.String())
+	}
+	for _, l := range []logic.Literal{0, 27, -27} {
+		if _, err := logic.NewClause(l); err == nil {
+			t.Errorf("NewClause(%d) returned no error", l)
+		}
+	}
+}
+
+func TestLiteralString(t *testing.T) {
+	for l, expected := range map[logic.Literal]string{1: "A", -2: "-B", 26: "Z"} {
+		if l.String() != expected {
+			t.Errorf("Literal(%d).String() = %q; want %q", int(l), l.String(), expected)
+		}
+	}
+}
+
+func TestProofWrite(t *testing.T) {
+	set, err := logic.ParseClauses("A", "-A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := logic.NewSolver(logic.EngineHorn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := s.Solve(context.Background(), set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := result.Proof.Write(&sb); err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != "0\n" {
+		t.Errorf("Proof.Write() = %q; want %q", sb.String(), "0\n")
+	}
+}
+
+func TestParseClauses(t *testing.T) {
+	if _, err := logic.ParseClauses("A,B", "A,?"); err == nil {
+		t.Errorf("ParseClauses accepted an invalid clause")
+	}
+}
+
+func Example() {
+	clauses, err := logic.ParseClauses("A,B", "-A,B", "A,-B", "-A,-B")
+	if err != nil {
+		panic(err)
+	}
+	s, err := logic.NewSolver(logic.EngineAuto)
+	if err != nil {
+		panic(err)
+	}
+	result, err := s.Solve(context.Background(), clauses)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(result.Verdict, "by", result.Engine)
+	// Output: unsatisfiable by 2-sat
+}
+
+func TestIncremental(t *testing.T) {
+	s := logic.NewIncremental()
+	clauses, err := logic.ParseClauses("-A,B", "-B,C")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Add(clauses...)
+	result, err := s.Solve(context.Background(), []logic.Literal{1, -3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != logic.Unsatisfiable || !reflect.DeepEqual(result.Failed, []logic.Literal{1, -3}) {
+		t.Errorf("Solve(A, -C) = %v with failed %v; want unsatisfiable with failed [1 -3]", result.Verdict, result.Failed)
+	}
+	result, err = s.Solve(context.Background(), []logic.Literal{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != logic.Satisfiable || !reflect.DeepEqual(result.Model, []logic.Literal{1, 2, 3}) {
+		t.Errorf("Solve(A) = %v with model %v; want satisfiable with model [1 2 3]", result.Verdict, result.Model)
+	}
+}
+
+func TestIncrementalScopes(t *testing.T) {
+	s := logic.NewIncremental()
+	base, err := logic.ParseClauses("A,B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Add(base...)
+	s.Push()
+	query, err := logic.ParseClauses("-A", "-B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Add(query...)
+	if result, err := s.Solve(context.Background(), nil); err != nil || result.Verdict != logic.Unsatisfiable {
+		t.Errorf("Solve() in scope = %v, %v; want unsatisfiable", result.Verdict, err)
+	}
+	if err := s.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := s.Solve(context.Background(), nil); err != nil || result.Verdict != logic.Satisfiable {
+		t.Errorf("Solve() after Pop = %v, %v; want satisfiable", result.Verdict, err)
+	}
+	if err := s.Pop(); err == nil {
+		t.Errorf("Pop() without scope returned no error")
+	}
+}
+
+func TestBackbone(t *testing.T) {
+	clauses, err := logic.ParseClauses("A,B", "A,-B", "-A,C,D")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := logic.Backbone(context.Background(), clauses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Verdict != logic.Satisfiable || !reflect.DeepEqual(result.Forced, []logic.Literal{1}) || !reflect.DeepEqual(result.Unforced, []logic.Literal{2, 3, 4}) {
+		t.Errorf("Backbone() = %v, forced %v, unforced %v; want satisfiable, forced [1], unforced [2 3 4]", result.Verdict, result.Forced, result.Unforced)
+	}
+}
@@ -0,0 +1,52 @@
+package logic
+
+import (
+	"io"
+
+	"github.com/thxrsxm/res/internal/drat"
+)
+
+// ProofStep adds or deletes a single clause of a proof.
+type ProofStep struct {
+	// Delete is true if the clause is deleted, false if it is added.
+	Delete bool
+	// Clause lists the literals of the clause.
+	Clause []Literal
+}
+
+// Proof is a DRAT proof of unsatisfiability: every added clause follows from the clauses before it.
+type Proof struct {
+	Steps []ProofStep
+}
+
+// Write writes the proof in the DRAT text format, one step per line terminated by 0,
+// deletions prefixed with "d".
+func (p *Proof) Write(w io.Writer) error {
+	return p.internal().Write(w)
+}
+
+// WriteBinary writes the proof in the binary DRAT format.
+func (p *Proof) WriteBinary(w io.Writer) error {
+	return p.internal().WriteBinary(w)
+}
+
+// internal converts the proof to a proof of the internal packages.
+func (p *Proof) internal() *drat.Proof {
+	result := &drat.Proof{Steps: make([]drat.Step, len(p.Steps))}
+	for i, step := range p.Steps {
+		result.Steps[i] = drat.Step{Delete: step.Delete, Clause: toLiterals(step.Clause)}
+	}
+	return result
+}
+
+// fromProof converts a proof of the internal packages, keeping nil.
+func fromProof(p *drat.Proof) *Proof {
+	if p == nil {
+		return nil
+	}
+	result := &Proof{Steps: make([]ProofStep, len(p.Steps))}
+	for i, step := range p.Steps {
+		result.Steps[i] = ProofStep{Delete: step.Delete, Clause: fromLiterals(step.Clause)}
+	}
+	return result
+}
@@ -2,53 +2,380 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/drat"
+	"github.com/thxrsxm/res/internal/lrat"
+	"github.com/thxrsxm/res/internal/preprocess"
+	"github.com/thxrsxm/res/internal/solver"
+	"github.com/thxrsxm/res/internal/stats"
+	"github.com/thxrsxm/res/internal/tracecheck"
 )
 
+// command describes a subcommand of the CLI.
+type command struct {
+	// summary is the one-line description shown in the usage message.
+	summary string
+	// run executes the subcommand with the arguments following its name.
+	run func(args []string) error
+}
+
+// commands maps subcommand names to their implementations.
+var commands = map[string]command{
+	"backbone":    {"Print the literals true in every model and the variables not forced", runBackbone},
+	"bench":       {"Measure every engine on a directory of instances", runBench},
+	"check-proof": {"Check a hand-written resolution proof", runCheckProof},
+	"count":       {"Print the number of satisfying assignments", runCount},
+	"dp":          {"Run the Davis–Putnam procedure and print a per-variable trace", runDP},
+	"gen":         {"Generate random and structured clause sets", runGen},
+	"models":      {"Print every satisfying assignment", runModels},
+	"primes":      {"Print the prime implicates, or the prime implicants of a DNF", runPrimes},
+	"repl":        {"Build and query a clause set interactively", runRepl},
+	"table":       {"Print the truth table of the clause set", runTable},
+	"tutor":       {"Walk through the resolution saturation round by round", runTutor},
+}
+
 func main() {
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: res [options] <clause1> <clause2> ...\n")
-		fmt.Fprintf(os.Stderr, "\n")
-		fmt.Fprintf(os.Stderr, "A resolution theorem prover for propositional logic.\n")
-		fmt.Fprintf(os.Stderr, "\n")
-		fmt.Fprintf(os.Stderr, "Arguments:\n")
-		fmt.Fprintf(os.Stderr, "  <clause>    A clause in the format: A,B,-C (comma-separated literals)\n")
-		fmt.Fprintf(os.Stderr, "              Each literal is a single letter (A-Z) optionally prefixed with '-'\n")
-		fmt.Fprintf(os.Stderr, "\n")
-		fmt.Fprintf(os.Stderr, "Output:\n")
-		fmt.Fprintf(os.Stderr, "  [ ]         The clause set is unsatisfiable (contradiction found)\n")
-		fmt.Fprintf(os.Stderr, "  [x]         The clause set is satisfiable (no contradiction found)\n")
-		fmt.Fprintf(os.Stderr, "\n")
-		fmt.Fprintf(os.Stderr, "Examples:\n")
-		fmt.Fprintf(os.Stderr, "  res a,-a\n")
-		fmt.Fprintf(os.Stderr, "  res \"a,b\" \"-a,c\" \"-b,c\" \"-c\"\n")
-		fmt.Fprintf(os.Stderr, "  res a,b,-c -a,b,c -b,c -c\n")
-		fmt.Fprintf(os.Stderr, "  res -- -a,b,-c -a,b,c -b,c -c\n")
-	}
-	// Stop flag parsing after first non-flag argument
+	showModel := flag.Bool("model", false, "print a satisfying assignment if the engine produces one")
+	explain := flag.Bool("explain", false, "print why a 2-CNF clause set is unsatisfiable")
+	var showStats statsFlag
+	flag.Var(&showStats, "stats", "print solver statistics as text or json")
+	dratPath := flag.String("drat", "", "write a DRAT proof of an unsatisfiable clause set to `file`")
+	dratBinary := flag.Bool("drat-binary", false, "write the DRAT proof in binary format")
+	lratPath := flag.String("lrat", "", "write an LRAT proof of an unsatisfiable clause set to `file`")
+	tracePath := flag.String("tracecheck", "", "write a TraceCheck resolution trace of an unsatisfiable clause set to `file`")
+	workers := flag.Int("workers", 1, "number of goroutines resolution saturation runs on")
+	engine := flag.String("engine", solver.EngineAuto, "`name` of the engine deciding the clause set")
+	stages := flag.String("preprocess", "", "simplify the clause set with the comma-separated `stages` before deciding it")
+	flag.Usage = usage
 	flag.CommandLine.SetOutput(os.Stderr)
 	flag.Parse()
 	if len(flag.Args()) == 0 {
 		flag.Usage()
 		os.Exit(0)
 	}
+	if cmd, ok := commands[flag.Arg(0)]; ok {
+		// The options only apply to deciding a clause set, so reject them instead of ignoring them
+		given := []string{}
+		flag.Visit(func(f *flag.Flag) { given = append(given, "--"+f.Name) })
+		if len(given) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: %s cannot be used with the %s command\n", strings.Join(given, ", "), flag.Arg(0))
+			os.Exit(2)
+		}
+		if err := cmd.run(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	set, err := parseClauses(flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		os.Exit(1)
+	}
+	if *workers < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --workers must be at least 1\n")
+		os.Exit(1)
+	}
+	result, err := solvePreprocessed(*stages, *engine, set, solver.Options{Workers: *workers})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	printResult(result.Unsatisfiable)
+	if *engine == solver.EnginePortfolio && showStats == "" {
+		fmt.Printf("engine: %s\n", result.Engine)
+	}
+	if *showModel && result.Model != nil {
+		fmt.Printf("model: %s\n", formatModel(result.Model))
+	}
+	if *explain && result.Conflict != nil {
+		fmt.Printf("conflict: %s\n", result.Conflict)
+	}
+	if err := printStats(showStats, result); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *dratPath != "" {
+		if err := writeDRAT(*dratPath, *dratBinary, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *lratPath != "" || *tracePath != "" {
+		if err := writeRefutation(*lratPath, *tracePath, set, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// solvePreprocessed decides the clause set with the named engine after simplifying it with the
+// preprocessing stages, if any. The model is mapped back to the original clause set and the DRAT
+// proof starts with the simplification steps. The refutation is dropped, as its steps resolve
+// the simplified clauses.
+func solvePreprocessed(stages, engine string, set []clause.Clause, opts solver.Options) (solver.Result, error) {
+	if stages == "" {
+		return solver.SolveEngine(context.Background(), engine, set, opts)
+	}
+	selected, err := preprocess.ParseStages(stages)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	st := &stats.Stats{}
+	proof := &drat.Proof{}
+	simplified, rec := preprocess.Run(set, selected, st, proof)
+	result, err := solver.SolveEngine(context.Background(), engine, simplified, opts)
+	if err != nil {
+		return solver.Result{}, err
+	}
+	result.Stats.Subsumptions += st.Subsumptions
+	result.Refutation = nil
+	if result.Model != nil {
+		result.Model = rec.Model(result.Model)
+	}
+	if result.Proof != nil {
+		proof.Steps = append(proof.Steps, result.Proof.Steps...)
+		result.Proof = proof
+	}
+	return result, nil
+}
+
+// writeDRAT writes the proof of an unsatisfiable result to a file.
+// Nothing is written for a satisfiable result.
+func writeDRAT(path string, binary bool, result solver.Result) error {
+	if !result.Unsatisfiable {
+		fmt.Fprintf(os.Stderr, "no DRAT proof written: the clause set is satisfiable\n")
+		return nil
+	}
+	if result.Proof == nil {
+		return fmt.Errorf("engine %s does not produce proofs", result.Engine)
+	}
+	return writeFile(path, func(w io.Writer) error {
+		if binary {
+			return result.Proof.WriteBinary(w)
+		}
+		return result.Proof.Write(w)
+	})
+}
+
+// writeRefutation writes the resolution refutation of an unsatisfiable result as LRAT proof
+// and/or TraceCheck trace, skipping empty paths. Results of other engines than resolution
+// are refuted again with clause.Refute. Nothing is written for a satisfiable result.
+func writeRefutation(lratPath, tracePath string, set []clause.Clause, result solver.Result) error {
+	if !result.Unsatisfiable {
+		fmt.Fprintf(os.Stderr, "no refutation written: the clause set is satisfiable\n")
+		return nil
+	}
+	steps := result.Refutation
+	if steps == nil {
+		_, steps = clause.Refute(set, nil)
+	}
+	if lratPath != "" {
+		err := writeFile(lratPath, func(w io.Writer) error {
+			return lrat.Write(w, lrat.FromRefutation(set, steps))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if tracePath != "" {
+		return writeFile(tracePath, func(w io.Writer) error {
+			return tracecheck.Write(w, tracecheck.FromRefutation(set, steps))
+		})
+	}
+	return nil
+}
+
+// writeFile creates or truncates a file and fills it with write.
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// statsFlag is the value of the --stats flag: empty (off), "text" or "json".
+// A bare --stats selects text.
+type statsFlag string
+
+func (f *statsFlag) String() string { return string(*f) }
+
+func (f *statsFlag) Set(s string) error {
+	switch s {
+	case "true", "text":
+		*f = "text"
+	case "false":
+		*f = ""
+	case "json":
+		*f = "json"
+	default:
+		return fmt.Errorf("unknown format: %q", s)
+	}
+	return nil
+}
+
+func (f *statsFlag) IsBoolFlag() bool { return true }
+
+// printStats prints the statistics of the result in the selected format.
+func printStats(format statsFlag, result solver.Result) error {
+	switch format {
+	case "text":
+		fmt.Printf("engine:          %s\n", result.Engine)
+		fmt.Print(result.Stats.String())
+	case "json":
+		data, err := json.MarshalIndent(struct {
+			Engine string `json:"engine"`
+			stats.Stats
+		}{result.Engine, result.Stats}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	}
+	return nil
+}
+
+// usage prints the usage message of the CLI.
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: res [options] <clause1> <clause2> ...\n")
+	fmt.Fprintf(os.Stderr, "       res <command> [options] <clause1> <clause2> ...\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "A resolution theorem prover for propositional logic.\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "Arguments:\n")
+	fmt.Fprintf(os.Stderr, "  <clause>    A clause in the format: A,B,-C (comma-separated literals)\n")
+	fmt.Fprintf(os.Stderr, "              Each literal is a single letter (A-Z) optionally prefixed with '-'\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	fmt.Fprintf(os.Stderr, "  --model     Print a satisfying assignment if the engine produces one\n")
+	fmt.Fprintf(os.Stderr, "  --explain   Print the implication cycle of an unsatisfiable 2-CNF clause set\n")
+	fmt.Fprintf(os.Stderr, "  --stats     Print solver statistics (--stats=json for JSON)\n")
+	fmt.Fprintf(os.Stderr, "  --drat file Write a DRAT proof of an unsatisfiable clause set to file\n")
+	fmt.Fprintf(os.Stderr, "  --drat-binary\n")
+	fmt.Fprintf(os.Stderr, "              Write the DRAT proof in binary format\n")
+	fmt.Fprintf(os.Stderr, "  --lrat file Write an LRAT proof of an unsatisfiable clause set to file\n")
+	fmt.Fprintf(os.Stderr, "  --tracecheck file\n")
+	fmt.Fprintf(os.Stderr, "              Write a TraceCheck resolution trace of an unsatisfiable clause set to file\n")
+	fmt.Fprintf(os.Stderr, "  --workers N Saturate with resolution on N goroutines (default 1)\n")
+	fmt.Fprintf(os.Stderr, "  --engine name\n")
+	fmt.Fprintf(os.Stderr, "              Decide the clause set with the named engine (default auto):\n")
+	fmt.Fprintf(os.Stderr, "              %s\n", strings.Join(engineNames(), ", "))
+	fmt.Fprintf(os.Stderr, "  --preprocess stages\n")
+	fmt.Fprintf(os.Stderr, "              Simplify the clause set before deciding it with the comma-separated stages:\n")
+	fmt.Fprintf(os.Stderr, "              all, %s\n", strings.Join(preprocess.Stages, ", "))
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "Output:\n")
+	fmt.Fprintf(os.Stderr, "  [ ]         The clause set is unsatisfiable (contradiction found)\n")
+	fmt.Fprintf(os.Stderr, "  [x]         The clause set is satisfiable (no contradiction found)\n")
+	fmt.Fprintf(os.Stderr, "\n")
+	fmt.Fprintf(os.Stderr, "Examples:\n")
+	fmt.Fprintf(os.Stderr, "  res a,-a\n")
+	fmt.Fprintf(os.Stderr, "  res \"a,b\" \"-a,c\" \"-b,c\" \"-c\"\n")
+	fmt.Fprintf(os.Stderr, "  res a,b,-c -a,b,c -b,c -c\n")
+	fmt.Fprintf(os.Stderr, "  res -- -a,b,-c -a,b,c -b,c -c\n")
+	fmt.Fprintf(os.Stderr, "  res --model a,-b -a\n")
+	fmt.Fprintf(os.Stderr, "  res --stats=json -- a,b -a,b a,-b -a,-b\n")
+	fmt.Fprintf(os.Stderr, "  res --drat proof.drat -- a,b,c a,b,-c a,-b -a,b -a,-b\n")
+	fmt.Fprintf(os.Stderr, "  res --engine=portfolio -- a,b,c a,b,-c a,-b -a,b -a,-b\n")
+	fmt.Fprintf(os.Stderr, "  res --preprocess all --model -- a,b -a,c -b,-c d,-c\n")
+	fmt.Fprintf(os.Stderr, "  res dp -- a,b -a,c -b,c -c\n")
+	fmt.Fprintf(os.Stderr, "  res count --project a,b -- a,b -a,c\n")
+	fmt.Fprintf(os.Stderr, "  res table -- a,b -a\n")
+	fmt.Fprintf(os.Stderr, "  res tutor --no-pause -- a,b -a,b -b\n")
+	fmt.Fprintf(os.Stderr, "  res repl\n")
+	fmt.Fprintf(os.Stderr, "  res check-proof --proof proof.txt -- a,b -a,b -b\n")
+	fmt.Fprintf(os.Stderr, "  res bench --format json --baseline baseline.json instances/\n")
+	fmt.Fprintf(os.Stderr, "  res gen random -k 3 -n 10 -m 42 -seed 7 -format dimacs\n")
+	fmt.Fprintf(os.Stderr, "  res backbone -- a,b a,-b -a,c,d\n")
+	fmt.Fprintf(os.Stderr, "  res primes --max-size 2 -- a,b -a,c\n")
+	fmt.Fprintf(os.Stderr, "  res models --limit 2 --project a,b -- a,b -a,c\n")
+}
+
+// engineNames returns the names of the engines of the solver.
+func engineNames() []string {
+	names := []string{}
+	for _, e := range solver.Engines() {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// parseClauses parses every argument into a clause.
+func parseClauses(args []string) ([]clause.Clause, error) {
 	set := []clause.Clause{}
-	for i := range flag.Args() {
-		c, err := clause.Parse(flag.Args()[i])
+	for i := range args {
+		c, err := clause.Parse(args[i])
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing clause %q: %v\n", flag.Args()[i], err)
-			os.Exit(1)
+			return nil, fmt.Errorf("parsing clause %q: %v", args[i], err)
 		}
 		set = append(set, *c)
 	}
-	result := clause.Res(set, 0)
-	if result {
+	return set, nil
+}
+
+// parseProjection parses the variables of a projection given in clause format.
+// Returns nil for an empty string.
+func parseProjection(s string) ([]clause.Literal, error) {
+	if s == "" {
+		return nil, nil
+	}
+	c, err := clause.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("parsing projection %q: %v", s, err)
+	}
+	return clause.Variables([]clause.Clause{*c}), nil
+}
+
+// printResult prints the verdict for a clause set.
+func printResult(unsatisfiable bool) {
+	if unsatisfiable {
 		fmt.Println("[ ]")
 	} else {
 		fmt.Println("[x]")
 	}
 }
+
+// formatClause returns a clause in the input format of the CLI, e.g. A,-B,C.
+func formatClause(c clause.Clause) string {
+	s := ""
+	for i, l := range c.Literals() {
+		if i > 0 {
+			s += ","
+		}
+		s += clause.Lit2Str(l)
+	}
+	return s
+}
+
+// formatModel returns the true literals of a model in set notation.
+func formatModel(model []clause.Literal) string {
+	c := clause.New()
+	for _, l := range model {
+		c.Insert(l)
+	}
+	return c.String()
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/models"
+)
+
+// runModels prints every satisfying assignment of the clause set, one per line, as soon as it is found.
+func runModels(args []string) error {
+	fs := flag.NewFlagSet("models", flag.ExitOnError)
+	limit := fs.Int("limit", 0, "stop after `N` models (0 means no limit)")
+	project := fs.String("project", "", "project models onto the `variables` of a clause, e.g. A,B,C")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res models [options] [--] <clause1> <clause2> ...\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	set, err := parseClauses(fs.Args())
+	if err != nil {
+		return err
+	}
+	vars, err := parseProjection(*project)
+	if err != nil {
+		return err
+	}
+	models.Enumerate(set, vars, *limit, func(model []clause.Literal) bool {
+		fmt.Println(formatModel(model))
+		return true
+	})
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thxrsxm/res/internal/clause"
+	"github.com/thxrsxm/res/internal/primes"
+)
+
+// runPrimes prints the prime implicates of the clause set, or the prime implicants of the DNF
+// whose terms are given in clause format, shortest first. With --stream every prime is printed
+// as soon as the saturation derives it.
+func runPrimes(args []string) error {
+	fs := flag.NewFlagSet("primes", flag.ExitOnError)
+	implicants := fs.Bool("implicants", false, "read the arguments as terms of a DNF and print its prime implicants")
+	maxSize := fs.Int("max-size", 0, "only print primes of at most `N` literals (0 means no limit); longer clauses are still derived")
+	stream := fs.Bool("stream", false, "print every prime as soon as it is derived, in derivation order and without the total")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res primes [options] [--] <clause1> <clause2> ...\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *maxSize < 0 {
+		return fmt.Errorf("--max-size must not be negative")
+	}
+	set, err := parseClauses(fs.Args())
+	if err != nil {
+		return err
+	}
+	found := []clause.Clause{}
+	report := func(c clause.Clause) bool {
+		if *stream {
+			fmt.Println(c.String())
+		} else {
+			found = append(found, c)
+		}
+		return true
+	}
+	kind := "implicate"
+	switch {
+	case *implicants && *stream:
+		primes.StreamImplicants(set, *maxSize, nil, report)
+		return nil
+	case *stream:
+		primes.StreamImplicates(set, *maxSize, nil, report)
+		return nil
+	case *implicants:
+		kind = "implicant"
+		primes.Implicants(set, *maxSize, nil, report)
+	default:
+		primes.Implicates(set, *maxSize, nil, report)
+	}
+	for i := range found {
+		fmt.Println(found[i].String())
+	}
+	if len(found) != 1 {
+		kind += "s"
+	}
+	fmt.Printf("%d prime %s\n", len(found), kind)
+	return nil
+}
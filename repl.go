@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thxrsxm/res/internal/repl"
+)
+
+// runRepl starts an interactive session on standard input.
+func runRepl(args []string) error {
+	fs := flag.NewFlagSet("repl", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res repl\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprint(os.Stderr, repl.Help)
+	}
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	s := repl.New()
+	s.Prompt = "res> "
+	return s.Run(os.Stdin, os.Stdout)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+// runTable prints the truth table of the clause set: every assignment of its variables
+// together with the value of each clause and of the whole set.
+func runTable(args []string) error {
+	fs := flag.NewFlagSet("table", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res table [--] <clause1> <clause2> ...\n")
+	}
+	fs.Parse(args)
+	set, err := parseClauses(fs.Args())
+	if err != nil {
+		return err
+	}
+	vars := clause.Variables(set)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.AlignRight)
+	for _, v := range vars {
+		fmt.Fprintf(w, "%s\t", clause.Lit2Str(v))
+	}
+	fmt.Fprint(w, "|\t")
+	for i := range set {
+		fmt.Fprintf(w, "%s\t", set[i].String())
+	}
+	fmt.Fprint(w, "|\tresult\t\n")
+	satisfiable := false
+	clause.Assignments(vars, func(assignment map[clause.Literal]bool) bool {
+		for _, v := range vars {
+			fmt.Fprintf(w, "%s\t", bit(assignment[v]))
+		}
+		fmt.Fprint(w, "|\t")
+		result := true
+		for i := range set {
+			value := set[i].Eval(assignment)
+			result = result && value
+			fmt.Fprintf(w, "%s\t", bit(value))
+		}
+		fmt.Fprintf(w, "|\t%s\t\n", bit(result))
+		satisfiable = satisfiable || result
+		return true
+	})
+	w.Flush()
+	printResult(!satisfiable)
+	return nil
+}
+
+// bit formats a truth value as 1 or 0.
+func bit(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/thxrsxm/res/internal/clause"
+)
+
+// runTutor walks through the saturation of a clause set round by round.
+// Every round prints the current clause set, the resolvable pairs with their pivot
+// and the new resolvents, and waits for Enter unless --no-pause is given.
+func runTutor(args []string) error {
+	fs := flag.NewFlagSet("tutor", flag.ExitOnError)
+	noPause := fs.Bool("no-pause", false, "run to completion without waiting for Enter")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: res tutor [--no-pause] [--] <clause1> <clause2> ...\n")
+		fmt.Fprintf(os.Stderr, "\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	set, err := parseClauses(fs.Args())
+	if err != nil {
+		return err
+	}
+	input := bufio.NewReader(os.Stdin)
+	pause := !*noPause
+	for round, index := 1, 0; ; round++ {
+		fmt.Printf("Round %d\n", round)
+		fmt.Println("Clauses:")
+		for i := range set {
+			fmt.Printf("  %3d  %s\n", i+1, set[i].String())
+		}
+		if clause.Index(set, *clause.New()) >= 0 {
+			fmt.Println("The empty clause {} is in the set, so the set is unsatisfiable.")
+			printResult(true)
+			return nil
+		}
+		steps := clause.Round(set, index)
+		fmt.Println("Resolvable pairs:")
+		if len(steps) == 0 {
+			fmt.Println("  none")
+		}
+		size := len(set)
+		added := []clause.Step{}
+		for _, step := range steps {
+			note := ""
+			if i := clause.Index(set, step.Resolvent); i >= 0 {
+				note = fmt.Sprintf("  (already clause %d)", i+1)
+			} else {
+				set = append(set, step.Resolvent)
+				added = append(added, step)
+			}
+			fmt.Printf("  %3d, %3d on %-3s -> %s%s\n",
+				step.Left+1, step.Right+1, clause.Lit2Str(step.Pivot), step.Resolvent.String(), note)
+		}
+		fmt.Println("New resolvents:")
+		if len(added) == 0 {
+			fmt.Println("  none")
+		}
+		for i, step := range added {
+			fmt.Printf("  %3d  %s from %d, %d\n", size+i+1, step.Resolvent.String(), step.Left+1, step.Right+1)
+		}
+		if len(added) == 0 {
+			fmt.Println("No new clauses can be derived and the empty clause is not among them, so the set is satisfiable.")
+			printResult(false)
+			return nil
+		}
+		index = size
+		if pause {
+			fmt.Print("Press Enter to continue...")
+			if _, err := input.ReadString('\n'); err == io.EOF {
+				// Nothing left to read, finish without pausing
+				pause = false
+				fmt.Println()
+			} else if err != nil {
+				return err
+			}
+		}
+		fmt.Println()
+	}
+}